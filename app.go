@@ -17,6 +17,7 @@ type app struct {
 	freshrss       *freshrss.Client
 	freshrssSyncer *freshrss.Syncer
 	freshrssWorker *freshrss.Worker
+	freshrssEditor *freshrss.Editor
 }
 
 func bootstrap(ctx context.Context, outputToFile bool) (*app, error) {
@@ -55,6 +56,7 @@ func bootstrap(ctx context.Context, outputToFile bool) (*app, error) {
 	}
 
 	fw := freshrss.NewWorker(fr, store, writeToken)
+	fe := freshrss.NewEditor(fr, store, writeToken)
 
 	return &app{
 		cfg:            cfg,
@@ -62,6 +64,7 @@ func bootstrap(ctx context.Context, outputToFile bool) (*app, error) {
 		freshrss:       fr,
 		freshrssSyncer: fs,
 		freshrssWorker: fw,
+		freshrssEditor: fe,
 	}, nil
 }
 
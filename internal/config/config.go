@@ -33,6 +33,21 @@ type Config struct {
 		Username string `toml:"username"`
 		Password string `toml:"password"`
 	} `toml:"freshrss"`
+	Sync struct {
+		OnStartup    bool `toml:"on_startup"`
+		EveryMinutes int  `toml:"every_minutes"`
+	} `toml:"sync"`
+	Read struct {
+		OnOpen       bool     `toml:"on_open"`
+		OnScroll     bool     `toml:"on_scroll"`
+		AfterSeconds int      `toml:"after_seconds"`
+		ExcludeFeeds []string `toml:"exclude_feeds"`
+	} `toml:"read"`
+	Open struct {
+		Command  string `toml:"command"`
+		Terminal bool   `toml:"terminal"`
+	} `toml:"open"`
+	Keys map[string][]string `toml:"keys"`
 }
 
 func New() (*Config, error) {
@@ -46,8 +61,8 @@ func New() (*Config, error) {
 		return nil, err
 	}
 
-	var config *Config
-	if cerr := toml.Unmarshal(configRaw, &config); cerr != nil {
+	config := newDefault()
+	if cerr := toml.Unmarshal(configRaw, config); cerr != nil {
 		return nil, cerr
 	}
 
@@ -63,6 +78,22 @@ func New() (*Config, error) {
 	return config, nil
 }
 
+// newDefault returns the config with defaults of the options, that are used
+// when they're missing in the file, they match the ones in config.toml.
+func newDefault() *Config {
+	config := &Config{}
+	config.Sync.OnStartup = false
+	config.Sync.EveryMinutes = 0
+	config.Read.OnOpen = true
+	config.Read.OnScroll = false
+	config.Read.AfterSeconds = 0
+	config.Read.ExcludeFeeds = []string{}
+	config.Open.Command = ""
+	config.Open.Terminal = false
+	config.Keys = map[string][]string{}
+	return config
+}
+
 func Init() error {
 	configPath := MustGetConfigFilePath()
 	if isFileExists(configPath) {
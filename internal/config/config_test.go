@@ -5,10 +5,50 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/adrg/xdg"
 	"olexsmir.xyz/x/is"
 )
 
 func TestNewConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	t.Run("not initialized", func(t *testing.T) {
+		_, err := New()
+		is.Err(t, err, ErrNotInitializedConfig)
+	})
+
+	t.Run("template", func(t *testing.T) {
+		is.Err(t, Init(), nil)
+		is.Err(t, Init(), ErrConfigAlreadyExists)
+
+		c, err := New()
+		is.Err(t, err, nil)
+		is.Equal(t, c.FreshRSS.Username, "username")
+
+		def := newDefault()
+		is.Equal(t, c.Sync, def.Sync)
+		is.Equal(t, c.Read.OnOpen, def.Read.OnOpen)
+		is.Equal(t, c.Read.OnScroll, def.Read.OnScroll)
+		is.Equal(t, c.Read.AfterSeconds, def.Read.AfterSeconds)
+		is.Equal(t, len(c.Read.ExcludeFeeds), 0)
+		is.Equal(t, c.Open, def.Open)
+	})
+
+	t.Run("missing sections", func(t *testing.T) {
+		is.Err(t, os.WriteFile(MustGetConfigFilePath(), []byte(`[freshrss]
+host = "https://example.com/api/greader.php"
+username = "username"
+password = "password"
+`), 0o644), nil)
+
+		c, err := New()
+		is.Err(t, err, nil)
+		is.Equal(t, c.Read.OnOpen, true)
+		is.Equal(t, c.Keys != nil, true)
+	})
 }
 
 func TestParsePassword(t *testing.T) {
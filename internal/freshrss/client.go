@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -121,6 +122,7 @@ type ContentItem struct {
 	Title         string
 	Author        string
 	Canonical     []string
+	Alternate     []string
 	Content       string
 	Categories    []string
 	TimestampUsec string
@@ -173,6 +175,11 @@ func (g Client) StreamContents(ctx context.Context, opts StreamContents) ([]Cont
 				ci.Canonical = append(ci.Canonical, h)
 			}
 		}
+		for _, href := range item.Get("alternate.#.href").Array() {
+			if h := href.String(); h != "" {
+				ci.Alternate = append(ci.Alternate, h)
+			}
+		}
 		for _, cat := range item.Get("categories").Array() {
 			ci.Categories = append(ci.Categories, cat.String())
 		}
@@ -230,6 +237,32 @@ func (g Client) EditTag(ctx context.Context, writeToken string, opts EditTag) er
 	return err
 }
 
+type MarkAllAsRead struct {
+	// StreamID to operate on, feed, label or reading list (required)
+	StreamID string
+
+	// OlderThan is timestamp_usec, only items fetched before it are marked.
+	// Defaults to now on the server.
+	OlderThan int64
+}
+
+func (g Client) MarkAllAsRead(ctx context.Context, writeToken string, opts MarkAllAsRead) error {
+	if opts.StreamID == "" {
+		return ErrInvalidRequest
+	}
+
+	body := url.Values{}
+	body.Set("T", writeToken)
+	body.Set("s", opts.StreamID)
+	if opts.OlderThan != 0 {
+		body.Set("ts", strconv.FormatInt(opts.OlderThan, 10))
+	}
+
+	var resp string
+	err := g.postRequest(ctx, "/reader/api/0/mark-all-as-read", body, &resp)
+	return err
+}
+
 type EditSubscription struct {
 	// StreamID to operate on (required)
 	// `feed/1` - the id
@@ -268,6 +301,92 @@ func (g Client) SubscriptionEdit(ctx context.Context, token string, opts EditSub
 	return resp, err
 }
 
+type quickAdd struct {
+	StreamID string `json:"streamId"`
+	Error    string `json:"error"`
+}
+
+// QuickAdd subscribes to the feed url, and returns stream id of the new feed.
+func (g Client) QuickAdd(ctx context.Context, writeToken, feedURL string) (string, error) {
+	if feedURL == "" {
+		return "", ErrInvalidRequest
+	}
+
+	body := url.Values{}
+	body.Set("T", writeToken)
+	body.Set("quickadd", feedURL)
+
+	var resp quickAdd
+	if err := g.postRequest(ctx, "/reader/api/0/subscription/quickadd", body, &resp); err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf("%s", resp.Error)
+	}
+	if resp.StreamID == "" {
+		return "", fmt.Errorf("no feed found at %s", feedURL)
+	}
+	return resp.StreamID, nil
+}
+
+// RenameTag renames the tag (or folder), feeds and items in it keep it under the new id.
+func (g Client) RenameTag(ctx context.Context, writeToken, tagID, newTagID string) error {
+	if tagID == "" || newTagID == "" {
+		return ErrInvalidRequest
+	}
+
+	body := url.Values{}
+	body.Set("T", writeToken)
+	body.Set("s", tagID)
+	body.Set("dest", newTagID)
+
+	var resp string
+	err := g.postRequest(ctx, "/reader/api/0/rename-tag", body, &resp)
+	return err
+}
+
+// DisableTag deletes the tag (or folder), feeds in it are moved out of it.
+func (g Client) DisableTag(ctx context.Context, writeToken, tagID string) error {
+	if tagID == "" {
+		return ErrInvalidRequest
+	}
+
+	body := url.Values{}
+	body.Set("T", writeToken)
+	body.Set("s", tagID)
+
+	var resp string
+	err := g.postRequest(ctx, "/reader/api/0/disable-tag", body, &resp)
+	return err
+}
+
+// IsReachable reports whether a connection to the host can be established.
+func (g Client) IsReachable(ctx context.Context) bool {
+	u, err := url.Parse(g.host)
+	if err != nil {
+		return false
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	var d net.Dialer
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
 func setOption(b *url.Values, k, v string) {
 	if v != "" {
 		b.Set(k, v)
@@ -0,0 +1,190 @@
+package freshrss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+const labelPrefix = "user/-/label/"
+
+// LabelID returns stream id of the folder with the name.
+func LabelID(name string) string {
+	if name == "" || strings.HasPrefix(name, labelPrefix) {
+		return name
+	}
+	return labelPrefix + name
+}
+
+// LabelName returns name of the folder or label with the id.
+func LabelName(id string) string {
+	return strings.TrimPrefix(id, labelPrefix)
+}
+
+// labelIDs returns ids of user labels among the categories.
+func labelIDs(categories []SubscriptionCategory) []string {
+	var res []string
+	for _, cat := range categories {
+		if strings.HasPrefix(cat.ID, labelPrefix) {
+			res = append(res, cat.ID)
+		}
+	}
+	return res
+}
+
+var ErrSubscriptionNotFound = errors.New("subscription not found")
+
+// Editor changes subscriptions on the server, and applies the changes
+// to the store right away, so they're visible before the next sync.
+type Editor struct {
+	api   *Client
+	store *store.Sqlite
+
+	writeToken string
+}
+
+func NewEditor(api *Client, store *store.Sqlite, writeToken string) *Editor {
+	return &Editor{
+		api:        api,
+		store:      store,
+		writeToken: writeToken,
+	}
+}
+
+// Subscribe subscribes to the feed url, and puts it in the folder, if it's set.
+func (e *Editor) Subscribe(ctx context.Context, feedURL, folder string) (store.Feed, error) {
+	id, err := e.api.QuickAdd(ctx, e.writeToken, feedURL)
+	if err != nil {
+		return store.Feed{}, err
+	}
+
+	if folder != "" {
+		if _, err := e.api.SubscriptionEdit(ctx, e.writeToken, EditSubscription{
+			StreamID:      id,
+			Action:        "edit",
+			AddCategoryID: LabelID(folder),
+		}); err != nil {
+			return store.Feed{}, err
+		}
+	}
+
+	// the title and the folder are set by the server
+	subs, err := e.api.SubscriptionList(ctx)
+	if err != nil {
+		return store.Feed{}, err
+	}
+
+	for _, sub := range subs {
+		if sub.ID != id {
+			continue
+		}
+
+		if err := e.store.UpsertSubscription(ctx, sub.ID, sub.Title, sub.URL, sub.HTMLURL); err != nil {
+			return store.Feed{}, err
+		}
+
+		if err := e.store.SetFeedFolders(ctx, sub.ID, labelIDs(sub.Categories)...); err != nil {
+			return store.Feed{}, err
+		}
+
+		return store.Feed{ID: sub.ID, Title: sub.Title, URL: sub.URL, HTMLURL: sub.HTMLURL}, nil
+	}
+
+	return store.Feed{}, fmt.Errorf("%w: %s", ErrSubscriptionNotFound, feedURL)
+}
+
+// Unsubscribe unsubscribes from the feed, and deletes it with its articles.
+func (e *Editor) Unsubscribe(ctx context.Context, feedID string) error {
+	if _, err := e.api.SubscriptionEdit(ctx, e.writeToken, EditSubscription{
+		StreamID: feedID,
+		Action:   "unsubscribe",
+	}); err != nil {
+		return err
+	}
+	return e.store.DeleteFeed(ctx, feedID)
+}
+
+// Move moves the feed to the folder.
+func (e *Editor) Move(ctx context.Context, feedID, folder string) error {
+	if folder == "" {
+		return ErrInvalidRequest
+	}
+
+	folders, err := e.store.GetFeedFolders(ctx, feedID)
+	if err != nil {
+		return err
+	}
+
+	// feeds in freshrss are in one category, so adding a new one is enough,
+	// the old one is removed just in case other server behaves differently
+	opts := EditSubscription{
+		StreamID:      feedID,
+		Action:        "edit",
+		AddCategoryID: LabelID(folder),
+	}
+	if len(folders) > 0 && folders[0] != opts.AddCategoryID {
+		opts.Remove = folders[0]
+	}
+
+	if _, err := e.api.SubscriptionEdit(ctx, e.writeToken, opts); err != nil {
+		return err
+	}
+	return e.store.SetFeedFolders(ctx, feedID, opts.AddCategoryID)
+}
+
+// Rename changes title of the feed.
+func (e *Editor) Rename(ctx context.Context, feedID, title string) error {
+	if title == "" {
+		return ErrInvalidRequest
+	}
+
+	if _, err := e.api.SubscriptionEdit(ctx, e.writeToken, EditSubscription{
+		StreamID: feedID,
+		Action:   "edit",
+		Title:    title,
+	}); err != nil {
+		return err
+	}
+	return e.store.RenameFeed(ctx, feedID, title)
+}
+
+// RenameFolder renames the folder, feeds in it are kept.
+func (e *Editor) RenameFolder(ctx context.Context, name, newName string) error {
+	if name == "" || newName == "" {
+		return ErrInvalidRequest
+	}
+
+	id, newID := LabelID(name), LabelID(newName)
+	if err := e.api.RenameTag(ctx, e.writeToken, id, newID); err != nil {
+		return err
+	}
+
+	// the folder is renamed on the server, the next sync brings it,
+	// even if it isn't in the store yet
+	if err := e.store.RenameFolder(ctx, id, newID); err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// DeleteFolder deletes the folder, feeds in it are left without a folder.
+func (e *Editor) DeleteFolder(ctx context.Context, name string) error {
+	if name == "" {
+		return ErrInvalidRequest
+	}
+
+	id := LabelID(name)
+	if err := e.api.DisableTag(ctx, e.writeToken, id); err != nil {
+		return err
+	}
+
+	// the folder is deleted on the server, there's nothing left to do
+	// if it isn't in the store
+	if err := e.store.DeleteFolder(ctx, id); err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	return nil
+}
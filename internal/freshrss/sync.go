@@ -4,27 +4,86 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
 	"olexsmir.xyz/smutok/internal/store"
 )
 
+type SyncStage int
+
+const (
+	SyncTags SyncStage = iota
+	SyncSubscriptions
+	SyncUnreadItems
+	SyncUnreadStatuses
+	SyncStarredItems
+	SyncStarredStatuses
+	SyncDone
+)
+
+func (s SyncStage) String() string {
+	switch s {
+	case SyncTags:
+		return "tags"
+	case SyncSubscriptions:
+		return "subscriptions"
+	case SyncUnreadItems:
+		return "unread items"
+	case SyncUnreadStatuses:
+		return "unread statuses"
+	case SyncStarredItems:
+		return "starred items"
+	case SyncStarredStatuses:
+		return "starred statuses"
+	case SyncDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
+
+// SyncProgress is sent when sync moves to the next stage.
+type SyncProgress struct {
+	Stage SyncStage
+
+	// Step is number of the stage, starting from 1, out of Steps.
+	Step  int
+	Steps int
+
+	// Err is the result of sync, set only for [SyncDone].
+	Err error
+}
+
 type Syncer struct {
 	store *store.Sqlite
 	api   *Client
 
-	ot int64
+	ot       int64
+	progress chan SyncProgress
+
+	// folders of feeds, items list them among their labels
+	folders map[string][]string
 }
 
 func NewSyncer(api *Client, store *store.Sqlite) *Syncer {
 	return &Syncer{
-		store: store,
-		api:   api,
+		store:    store,
+		api:      api,
+		progress: make(chan SyncProgress, 16),
 	}
 }
 
-func (f *Syncer) Sync(ctx context.Context) error {
+// Progress returns channel with sync progress events.
+// Events are dropped if nobody reads them.
+func (f *Syncer) Progress() <-chan SyncProgress { return f.progress }
+
+func (f *Syncer) Sync(ctx context.Context) (err error) {
+	defer func() {
+		f.notify(SyncProgress{Stage: SyncDone, Step: int(SyncDone), Steps: int(SyncDone), Err: err})
+	}()
+
 	ot, err := f.getLastSyncTime(ctx)
 	if err != nil {
 		return err
@@ -35,31 +94,33 @@ func (f *Syncer) Sync(ctx context.Context) error {
 
 	// TODO: sync all articles once if it's initial sync
 
-	if err := f.syncTags(ctx); err != nil {
-		return err
-	}
-
-	if err := f.syncSubscriptions(ctx); err != nil {
-		return err
+	stages := []struct {
+		stage SyncStage
+		sync  func(context.Context) error
+	}{
+		{SyncTags, f.syncTags},
+		{SyncSubscriptions, f.syncSubscriptions},
+		{SyncUnreadItems, f.syncUnreadItems},
+		{SyncUnreadStatuses, f.syncUnreadItemsStatuses},
+		{SyncStarredItems, f.syncStarredItems},
+		{SyncStarredStatuses, f.syncStarredItemStatuses},
 	}
 
-	if err := f.syncUnreadItems(ctx); err != nil {
-		return err
-	}
-
-	if err := f.syncUnreadItemsStatuses(ctx); err != nil {
-		return err
+	for i, s := range stages {
+		f.notify(SyncProgress{Stage: s.stage, Step: i + 1, Steps: len(stages)})
+		if err := s.sync(ctx); err != nil {
+			return err
+		}
 	}
 
-	if err := f.syncStarredItems(ctx); err != nil {
-		return err
-	}
+	return f.store.SetLastSyncTime(ctx, newOt)
+}
 
-	if err := f.syncStarredItemStatuses(ctx); err != nil {
-		return err
+func (f *Syncer) notify(p SyncProgress) {
+	select {
+	case f.progress <- p:
+	default:
 	}
-
-	return f.store.SetLastSyncTime(ctx, newOt)
 }
 
 func (f *Syncer) getLastSyncTime(ctx context.Context) (int64, error) {
@@ -86,6 +147,7 @@ func (f *Syncer) syncTags(ctx context.Context) error {
 	}
 
 	var errs []error
+	var ids []string
 	for _, tag := range tags {
 		if strings.HasPrefix(tag.ID, "user/-/state/com.google/") &&
 			!strings.HasSuffix(tag.ID, StateStarred) {
@@ -95,6 +157,12 @@ func (f *Syncer) syncTags(ctx context.Context) error {
 		if err := f.store.UpsertTag(ctx, tag.ID); err != nil {
 			errs = append(errs, err)
 		}
+		ids = append(ids, tag.ID)
+	}
+
+	// delete local folders that were renamed or deleted on the server
+	if err := f.store.RemoveNonExistentFolders(ctx, ids); err != nil {
+		errs = append(errs, err)
 	}
 
 	slog.Info("finished tag sync", "errs", errs)
@@ -110,24 +178,16 @@ func (f *Syncer) syncSubscriptions(ctx context.Context) error {
 	}
 
 	var errs []error
+	f.folders = make(map[string][]string, len(subs))
 	for _, sub := range subs {
+		f.folders[sub.ID] = labelIDs(sub.Categories)
 		if err := f.store.UpsertSubscription(ctx, sub.ID, sub.Title, sub.URL, sub.HTMLURL); err != nil {
 			errs = append(errs, err)
 		}
 
-		for _, cat := range sub.Categories {
-			if !strings.Contains(cat.ID, "user/-/label") {
-				continue
-			}
-
-			// NOTE: probably redundant
-			// if err := g.store.UpsertTag(ctx, cat.ID); err != nil {
-			// 	errs = append(errs, err)
-			// }
-
-			if err := f.store.LinkFeedWithFolder(ctx, sub.ID, cat.ID); err != nil {
-				errs = append(errs, err)
-			}
+		// folders changed locally are reconciled with the server
+		if err := f.store.SetFeedFolders(ctx, sub.ID, f.folders[sub.ID]...); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
@@ -162,7 +222,7 @@ func (f *Syncer) syncUnreadItems(ctx context.Context) error {
 
 	var errs []error
 	for _, item := range items {
-		if err := f.store.UpsertArticle(ctx, item.TimestampUsec, item.Origin.StreamID, item.Title, item.Content, item.Author, item.Origin.HTMLURL, int(item.Published)); err != nil {
+		if err := f.upsertItem(ctx, item); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -171,6 +231,32 @@ func (f *Syncer) syncUnreadItems(ctx context.Context) error {
 	return errors.Join(errs...)
 }
 
+func (f *Syncer) upsertItem(ctx context.Context, item ContentItem) error {
+	if err := f.store.UpsertArticle(ctx, item.TimestampUsec, item.Origin.StreamID, item.Title, item.Content, item.Author, articleHref(item), int(item.Published)); err != nil {
+		return err
+	}
+
+	var labels []string
+	for _, c := range item.Categories {
+		if strings.HasPrefix(c, labelPrefix) && !slices.Contains(f.folders[item.Origin.StreamID], c) {
+			labels = append(labels, c)
+		}
+	}
+	return f.store.SetArticleLabels(ctx, item.TimestampUsec, labels...)
+}
+
+// articleHref returns url of the article, or of the feed's site if the item has none.
+func articleHref(item ContentItem) string {
+	switch {
+	case len(item.Canonical) > 0:
+		return item.Canonical[0]
+	case len(item.Alternate) > 0:
+		return item.Alternate[0]
+	default:
+		return item.Origin.HTMLURL
+	}
+}
+
 func (f *Syncer) syncUnreadItemsStatuses(ctx context.Context) error {
 	slog.Info("syncing unread items ids")
 
@@ -206,7 +292,7 @@ func (f *Syncer) syncStarredItems(ctx context.Context) error {
 
 	var errs []error
 	for _, item := range items {
-		if err := f.store.UpsertArticle(ctx, item.TimestampUsec, item.Origin.StreamID, item.Title, item.Content, item.Author, item.Origin.HTMLURL, int(item.Published)); err != nil {
+		if err := f.upsertItem(ctx, item); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -2,16 +2,27 @@ package freshrss
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"olexsmir.xyz/smutok/internal/store"
 )
 
+var ErrOffline = errors.New("server is unreachable")
+
 type Worker struct {
-	api   *Client
-	store *store.Sqlite
+	api     *Client
+	store   *store.Sqlite
+	errs    chan error
+	offline atomic.Bool
+
+	// pushing is held while actions are pushed, so they're pushed
+	// by one goroutine at a time
+	pushing chan struct{}
 
 	writeToken string
 }
@@ -20,10 +31,19 @@ func NewWorker(api *Client, store *store.Sqlite, writeToken string) *Worker {
 	return &Worker{
 		api:        api,
 		store:      store,
+		errs:       make(chan error, 16),
+		pushing:    make(chan struct{}, 1),
 		writeToken: writeToken,
 	}
 }
 
+// Offline reports whether the server was unreachable on the last push attempt.
+func (w *Worker) Offline() bool { return w.offline.Load() }
+
+// Errors returns channel with errors that occurred while pushing actions.
+// Errors are dropped if nobody reads them.
+func (w *Worker) Errors() <-chan error { return w.errs }
+
 func (w *Worker) Run(ctx context.Context) {
 	// TODO: get tick time from config ???
 	ticker := time.NewTicker(5 * time.Second)
@@ -37,37 +57,138 @@ func (w *Worker) Run(ctx context.Context) {
 		case <-ticker.C:
 			if !w.isNetworkAvailable(ctx) {
 				slog.Info("worker: no internet connection")
+				w.offline.Store(true)
 				continue
 			}
+			w.offline.Store(false)
+
+			if err := w.lock(ctx); err != nil {
+				return
+			}
+
+			// pushed first, so it doesn't override newer changes of single articles
+			if err := w.pendingMarkAllRead(ctx); err != nil {
+				w.reportErr(store.Read, err)
+			}
 
 			wg.Go(func() {
 				if err := w.pendingReads(ctx); err != nil {
-					slog.Error("worker: read", "err", err)
+					w.reportErr(store.Read, err)
 				}
 			})
 			wg.Go(func() {
 				if err := w.pendingUnreads(ctx); err != nil {
-					slog.Error("worker: unread", "err", err)
+					w.reportErr(store.Unread, err)
 				}
 			})
 			wg.Go(func() {
 				if err := w.pendingStar(ctx); err != nil {
-					slog.Error("worker: star", "err", err)
+					w.reportErr(store.Star, err)
 				}
 			})
 			wg.Go(func() {
 				if err := w.pendingUnstar(ctx); err != nil {
-					slog.Error("worker: unread", "err", err)
+					w.reportErr(store.Unstar, err)
+				}
+			})
+			wg.Go(func() {
+				if err := w.pendingLabels(ctx); err != nil {
+					w.reportErr(store.Label, err)
 				}
 			})
 			wg.Wait()
+			w.unlock()
 		}
 	}
 }
 
-// TODO: implement me
-func (Worker) isNetworkAvailable(_ context.Context) bool {
-	return true
+// Flush pushes all pending actions right away, not in batches of
+// [Worker.Run], until none are left or ctx is done.
+func (w *Worker) Flush(ctx context.Context) error {
+	if !w.isNetworkAvailable(ctx) {
+		w.offline.Store(true)
+		return ErrOffline
+	}
+
+	if err := w.lock(ctx); err != nil {
+		return err
+	}
+	defer w.unlock()
+
+	pushes := []struct {
+		action store.Action
+		push   func(context.Context) error
+	}{
+		{store.Read, w.pendingMarkAllRead},
+		{store.Read, w.pendingReads},
+		{store.Unread, w.pendingUnreads},
+		{store.Star, w.pendingStar},
+		{store.Unstar, w.pendingUnstar},
+		{store.Label, w.pendingLabels},
+	}
+
+	prev := -1
+	for {
+		n, err := w.store.CountPendingActions(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+
+		// every push sends at least one action, unless something's wrong
+		if n == prev {
+			return fmt.Errorf("%d actions can't be pushed", n)
+		}
+		prev = n
+
+		for _, p := range pushes {
+			if err := p.push(ctx); err != nil {
+				return fmt.Errorf("failed to push %s: %w", p.action, err)
+			}
+		}
+	}
+}
+
+// Undo reverts the last status change, see [store.Sqlite.UndoStatusChange].
+// It waits for actions that are being pushed, otherwise the change
+// could be taken for an unpushed one while it's on the way to the server.
+func (w *Worker) Undo(ctx context.Context) (store.StatusChange, error) {
+	if err := w.lock(ctx); err != nil {
+		return store.StatusChange{}, err
+	}
+	defer w.unlock()
+
+	return w.store.UndoStatusChange(ctx)
+}
+
+// lock waits until no actions are being pushed, or ctx is done.
+func (w *Worker) lock(ctx context.Context) error {
+	select {
+	case w.pushing <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) unlock() { <-w.pushing }
+
+func (w *Worker) reportErr(action store.Action, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	slog.Error("worker: "+action.String(), "err", err)
+	select {
+	case w.errs <- fmt.Errorf("failed to push %s: %w", action, err):
+	default:
+	}
+}
+
+func (w *Worker) isNetworkAvailable(ctx context.Context) bool {
+	return w.api.IsReachable(ctx)
 }
 
 func (w *Worker) pendingReads(ctx context.Context) error {
@@ -90,6 +211,52 @@ func (w *Worker) pendingUnstar(ctx context.Context) error {
 	return w.handle(ctx, store.Unstar, "", StateStarred)
 }
 
+func (w *Worker) pendingMarkAllRead(ctx context.Context) error {
+	slog.Debug("worker: pending mark all as read")
+	queued, err := w.store.GetPendingMarkAllRead(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range queued {
+		if err := w.api.MarkAllAsRead(ctx, w.writeToken, MarkAllAsRead{
+			StreamID:  m.StreamID,
+			OlderThan: m.OlderThan,
+		}); err != nil {
+			return err
+		}
+
+		if err := w.store.DeletePendingMarkAllRead(ctx, m.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Worker) pendingLabels(ctx context.Context) error {
+	slog.Debug("worker: pending labels")
+	changes, err := w.store.GetPendingLabelChanges(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		opts := EditTag{ItemID: c.ArticleIDs, TagToAdd: c.LabelID}
+		if c.Action == store.Unlabel {
+			opts = EditTag{ItemID: c.ArticleIDs, TagToRemove: c.LabelID}
+		}
+
+		if err := w.api.EditTag(ctx, w.writeToken, opts); err != nil {
+			return err
+		}
+
+		if err := w.store.DeletePendingLabelChange(ctx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (w *Worker) handle(ctx context.Context, action store.Action, addState, rmState string) error {
 	articleIDs, err := w.store.GetPendingActions(ctx, action)
 	if err != nil {
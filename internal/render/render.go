@@ -0,0 +1,554 @@
+// Package render converts article's html into styled text for the terminal.
+package render
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const minWidth = 10
+
+var (
+	accentColor = lipgloss.Color("62")
+	mutedColor  = lipgloss.Color("241")
+
+	h1Style     = lipgloss.NewStyle().Bold(true).Underline(true).Foreground(accentColor)
+	headerStyle = lipgloss.NewStyle().Bold(true).Foreground(accentColor)
+	linkStyle   = lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color("39"))
+	codeStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	preStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("250"))
+	mutedStyle  = lipgloss.NewStyle().Foreground(mutedColor)
+)
+
+type Link struct {
+	// Index is the footnote number of the link, starting from 1.
+	Index int
+	Text  string
+	URL   string
+}
+
+type Document struct {
+	Text  string
+	Links []Link
+}
+
+// Render renders html content wrapped to width, links are numbered
+// in order of appearance and listed at the end of the text.
+// Relative links are resolved against baseURL, the article's url.
+func Render(content, baseURL string, width int) (Document, error) {
+	root, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to parse html: %w", err)
+	}
+
+	r := &renderer{
+		width:     max(width, minWidth),
+		gap:       -1,
+		linkIndex: make(map[string]int),
+	}
+	if base, err := url.Parse(baseURL); err == nil && isWeb(base) {
+		r.base = base
+	}
+	r.walk(root)
+	r.flush()
+	r.footnotes()
+
+	return Document{
+		Text:  strings.TrimRight(r.out.String(), "\n"),
+		Links: r.links,
+	}, nil
+}
+
+type prefix struct {
+	first     string
+	rest      string
+	usedFirst bool
+}
+
+type list struct {
+	ordered bool
+	n       int
+}
+
+type renderer struct {
+	width int
+	out   strings.Builder
+
+	// inline content of the current block
+	inline    strings.Builder
+	lastSpace bool
+	style     lipgloss.Style
+
+	prefixes []*prefix
+	lists    []*list
+
+	// gap is the number of prefixes an empty line should be written with
+	// before the next line, or -1 if there's no pending empty line.
+	gap int
+
+	base      *url.URL
+	links     []Link
+	linkIndex map[string]int
+}
+
+func (r *renderer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.text(n.Data)
+		return
+	case html.ElementNode:
+		r.element(n)
+		return
+	}
+
+	r.children(n)
+}
+
+func (r *renderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.walk(c)
+	}
+}
+
+func (r *renderer) element(n *html.Node) {
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Head, atom.Noscript, atom.Template, atom.Iframe:
+		return
+
+	case atom.P, atom.Figure, atom.Dl:
+		r.block(func() { r.children(n) })
+
+	case atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer,
+		atom.Main, atom.Aside, atom.Nav, atom.Figcaption, atom.Dt, atom.Details, atom.Summary:
+		r.flush()
+		r.children(n)
+		r.flush()
+
+	case atom.Dd:
+		r.flush()
+		r.indent("    ", "    ", func() { r.children(n) })
+
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		r.heading(n)
+
+	case atom.Ul, atom.Ol:
+		r.list(n)
+
+	case atom.Li:
+		r.listItem(n)
+
+	case atom.Blockquote:
+		r.block(func() {
+			bar := mutedStyle.Render("│ ")
+			r.indent(bar, bar, func() { r.children(n) })
+		})
+
+	case atom.Pre:
+		r.pre(n)
+
+	case atom.Table:
+		r.table(n)
+
+	case atom.Hr:
+		r.block(func() {
+			r.writeLine(mutedStyle.Render(strings.Repeat("─", r.lineWidth())))
+		})
+
+	case atom.Br:
+		r.flush()
+
+	case atom.Img:
+		alt := strings.TrimSpace(attr(n, "alt"))
+		if alt == "" {
+			r.styled(mutedStyle, "[image]")
+		} else {
+			r.styled(mutedStyle, "[image: "+alt+"]")
+		}
+
+	case atom.A:
+		r.link(n)
+
+	case atom.B, atom.Strong:
+		r.withStyle(r.style.Bold(true), n)
+	case atom.I, atom.Em, atom.Cite:
+		r.withStyle(r.style.Italic(true), n)
+	case atom.U, atom.Ins:
+		r.withStyle(r.style.Underline(true), n)
+	case atom.S, atom.Del, atom.Strike:
+		r.withStyle(r.style.Strikethrough(true), n)
+	case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+		r.withStyle(codeStyle, n)
+
+	default:
+		r.children(n)
+	}
+}
+
+// text appends text to the current block, collapsing white spaces,
+// control characters are dropped, so the article can't control the terminal.
+func (r *renderer) text(s string) {
+	var b strings.Builder
+	for _, c := range s {
+		if unicode.IsSpace(c) {
+			if !r.lastSpace && r.inline.Len()+b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			r.lastSpace = true
+			continue
+		}
+		if unicode.IsControl(c) {
+			continue
+		}
+		b.WriteRune(c)
+		r.lastSpace = false
+	}
+
+	if b.Len() > 0 {
+		r.inline.WriteString(r.style.Render(b.String()))
+	}
+}
+
+func (r *renderer) styled(style lipgloss.Style, s string) {
+	prev := r.style
+	r.style = style
+	r.text(s)
+	r.style = prev
+}
+
+func (r *renderer) withStyle(style lipgloss.Style, n *html.Node) {
+	prev := r.style
+	r.style = style
+	r.children(n)
+	r.style = prev
+}
+
+func (r *renderer) link(n *html.Node) {
+	href, ok := r.resolve(strings.TrimSpace(attr(n, "href")))
+	if !ok {
+		r.children(n)
+		return
+	}
+
+	idx, ok := r.linkIndex[href]
+	if !ok {
+		idx = len(r.links) + 1
+		r.linkIndex[href] = idx
+		r.links = append(r.links, Link{
+			Index: idx,
+			Text:  Sanitize(strings.Join(strings.Fields(textContent(n)), " ")),
+			URL:   href,
+		})
+	}
+
+	r.withStyle(r.style.Inherit(linkStyle), n)
+	r.styled(mutedStyle, "["+strconv.Itoa(idx)+"]")
+}
+
+// resolve returns absolute url of the link, if it's a web link,
+// links to the article itself, scripts, files, and so on are dropped.
+func (r *renderer) resolve(href string) (string, bool) {
+	if href == "" || strings.HasPrefix(href, "#") {
+		return "", false
+	}
+
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+	if r.base != nil {
+		u = r.base.ResolveReference(u)
+	}
+	if !isWeb(u) {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// IsWebURL reports whether s is an absolute http or https url.
+func IsWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && isWeb(u)
+}
+
+func isWeb(u *url.URL) bool {
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// block renders fn separated by empty lines from surrounding content.
+func (r *renderer) block(fn func()) {
+	r.flush()
+	r.addGap()
+	fn()
+	r.flush()
+	r.addGap()
+}
+
+func (r *renderer) addGap() {
+	if r.gap < 0 {
+		r.gap = len(r.prefixes)
+	}
+	r.gap = min(r.gap, len(r.prefixes))
+}
+
+func (r *renderer) indent(first, rest string, fn func()) {
+	r.prefixes = append(r.prefixes, &prefix{first: first, rest: rest})
+	fn()
+	r.flush()
+	r.prefixes = r.prefixes[:len(r.prefixes)-1]
+}
+
+func (r *renderer) heading(n *html.Node) {
+	style := headerStyle
+	if n.DataAtom == atom.H1 {
+		style = h1Style
+	}
+
+	r.block(func() {
+		prev := r.style
+		r.style = style
+		r.children(n)
+		r.style = prev
+	})
+}
+
+func (r *renderer) list(n *html.Node) {
+	nested := len(r.lists) > 0
+	r.lists = append(r.lists, &list{ordered: n.DataAtom == atom.Ol})
+	defer func() { r.lists = r.lists[:len(r.lists)-1] }()
+
+	if nested {
+		r.flush()
+		r.children(n)
+		r.flush()
+		return
+	}
+	r.block(func() { r.children(n) })
+}
+
+func (r *renderer) listItem(n *html.Node) {
+	r.flush()
+
+	bullet := "• "
+	if len(r.lists) > 0 {
+		l := r.lists[len(r.lists)-1]
+		l.n++
+		if l.ordered {
+			bullet = strconv.Itoa(l.n) + ". "
+		}
+	}
+
+	r.indent(bullet, strings.Repeat(" ", ansi.StringWidth(bullet)), func() { r.children(n) })
+}
+
+func (r *renderer) pre(n *html.Node) {
+	r.block(func() {
+		code := strings.ReplaceAll(textContent(n), "\t", "    ")
+		code = strings.Trim(code, "\n")
+		for line := range strings.SplitSeq(code, "\n") {
+			line = Sanitize(line)
+			for l := range strings.SplitSeq(ansi.Hardwrap(line, r.lineWidth(), true), "\n") {
+				r.writeLine(preStyle.Render(l))
+			}
+		}
+	})
+}
+
+func (r *renderer) table(n *html.Node) {
+	var rows [][]string
+	var header []bool
+	eachElement(n, atom.Tr, func(tr *html.Node) {
+		var cells []string
+		isHeader := true
+		for c := tr.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom != atom.Td && c.DataAtom != atom.Th {
+				continue
+			}
+			if c.DataAtom == atom.Td {
+				isHeader = false
+			}
+			cells = append(cells, Sanitize(strings.Join(strings.Fields(textContent(c)), " ")))
+		}
+		if len(cells) > 0 {
+			rows = append(rows, cells)
+			header = append(header, isHeader)
+		}
+	})
+	if len(rows) == 0 {
+		return
+	}
+
+	var cols int
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+
+	widths := make([]int, cols)
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], ansi.StringWidth(cell))
+		}
+	}
+	fitColumns(widths, r.lineWidth()-(cols-1)*3)
+
+	r.block(func() {
+		for i, row := range rows {
+			cells := make([]string, cols)
+			for j := range cols {
+				var cell string
+				if j < len(row) {
+					cell = ansi.Truncate(row[j], widths[j], "…")
+				}
+				cell += strings.Repeat(" ", max(widths[j]-ansi.StringWidth(cell), 0))
+				if header[i] {
+					cell = lipgloss.NewStyle().Bold(true).Render(cell)
+				}
+				cells[j] = cell
+			}
+			r.writeLine(strings.Join(cells, mutedStyle.Render(" │ ")))
+
+			if header[i] && i+1 < len(rows) && !header[i+1] {
+				seps := make([]string, cols)
+				for j, w := range widths {
+					seps[j] = strings.Repeat("─", w)
+				}
+				r.writeLine(mutedStyle.Render(strings.Join(seps, "─┼─")))
+			}
+		}
+	})
+}
+
+// fitColumns shrinks the widest columns until they fit into total width.
+func fitColumns(widths []int, total int) {
+	for {
+		sum, widest := 0, 0
+		for i, w := range widths {
+			sum += w
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if sum <= total || widths[widest] <= 3 {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+func (r *renderer) footnotes() {
+	if len(r.links) == 0 {
+		return
+	}
+
+	r.addGap()
+	for _, l := range r.links {
+		r.writeLine(mutedStyle.Render(ansi.Truncate("["+strconv.Itoa(l.Index)+"]: "+Sanitize(l.URL), r.lineWidth(), "…")))
+	}
+}
+
+// flush writes the inline content of current block, wrapped to the width.
+func (r *renderer) flush() {
+	text := strings.TrimSpace(r.inline.String())
+	r.inline.Reset()
+	r.lastSpace = false
+	if ansi.Strip(text) == "" {
+		return
+	}
+
+	for line := range strings.SplitSeq(ansi.Wordwrap(text, r.lineWidth(), ""), "\n") {
+		r.writeLine(line)
+	}
+}
+
+func (r *renderer) writeLine(line string) {
+	if r.gap >= 0 && r.out.Len() > 0 {
+		var gap strings.Builder
+		for _, p := range r.prefixes[:min(r.gap, len(r.prefixes))] {
+			gap.WriteString(p.rest)
+		}
+		r.out.WriteString(strings.TrimRight(gap.String(), " "))
+		r.out.WriteByte('\n')
+	}
+	r.gap = -1
+
+	for _, p := range r.prefixes {
+		if p.usedFirst {
+			r.out.WriteString(p.rest)
+		} else {
+			r.out.WriteString(p.first)
+			p.usedFirst = true
+		}
+	}
+	r.out.WriteString(strings.TrimRight(line, " "))
+	r.out.WriteByte('\n')
+}
+
+func (r *renderer) lineWidth() int {
+	w := r.width
+	for _, p := range r.prefixes {
+		w -= ansi.StringWidth(p.rest)
+	}
+	return max(w, minWidth)
+}
+
+// Sanitize drops control characters, e.g. escape sequences, from the text,
+// so it can be printed to the terminal, control white spaces become spaces.
+func Sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case !unicode.IsControl(r):
+			return r
+		case unicode.IsSpace(r):
+			return ' '
+		default:
+			return -1
+		}
+	}, s)
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		if n.Type == html.ElementNode && n.DataAtom == atom.Br {
+			b.WriteByte('\n')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+func eachElement(n *html.Node, a atom.Atom, fn func(*html.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if c.DataAtom == a {
+			fn(c)
+			continue
+		}
+		eachElement(c, a, fn)
+	}
+}
@@ -0,0 +1,87 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"olexsmir.xyz/x/is"
+)
+
+func render(t *testing.T, content string, width int) Document {
+	t.Helper()
+	doc, err := Render(content, "", width)
+	is.Err(t, err, nil)
+	doc.Text = ansi.Strip(doc.Text)
+	return doc
+}
+
+func TestRender(t *testing.T) {
+	t.Run("paragraphs", func(t *testing.T) {
+		doc := render(t, "<p>first   paragraph\nof text</p><p>second</p>", 80)
+		is.Equal(t, doc.Text, "first paragraph of text\n\nsecond")
+	})
+
+	t.Run("wraps to width", func(t *testing.T) {
+		doc := render(t, "<p>one two three four five six</p>", 14)
+		is.Equal(t, doc.Text, "one two three\nfour five six")
+	})
+
+	t.Run("lists", func(t *testing.T) {
+		doc := render(t, "<ul><li>one</li><li>two<ol><li>a</li><li>b</li></ol></li></ul>", 80)
+		is.Equal(t, doc.Text, "• one\n• two\n  1. a\n  2. b")
+	})
+
+	t.Run("blockquote", func(t *testing.T) {
+		doc := render(t, "<blockquote><p>one</p><p>two</p></blockquote>", 80)
+		is.Equal(t, doc.Text, "│ one\n│\n│ two")
+	})
+
+	t.Run("preformatted", func(t *testing.T) {
+		doc := render(t, "<pre>if x {\n\treturn\n}</pre>", 80)
+		is.Equal(t, doc.Text, "if x {\n    return\n}")
+	})
+
+	t.Run("table", func(t *testing.T) {
+		doc := render(t, "<table><tr><th>name</th><th>n</th></tr><tr><td>a</td><td>10</td></tr></table>", 80)
+		is.Equal(t, doc.Text, "name │ n\n─────┼───\na    │ 10")
+	})
+
+	t.Run("links", func(t *testing.T) {
+		doc := render(t, `<p><a href="https://a.com">a</a>, <a href="https://b.com">b</a>, <a href="https://a.com">again</a>, <a href="#top">top</a></p>`, 80)
+		is.Equal(t, doc.Text, "a[1], b[2], again[1], top\n\n[1]: https://a.com\n[2]: https://b.com")
+		is.Equal(t, len(doc.Links), 2)
+		is.Equal(t, doc.Links[1], Link{Index: 2, Text: "b", URL: "https://b.com"})
+	})
+
+	t.Run("resolves relative links", func(t *testing.T) {
+		doc, err := Render(`<a href="/about">a</a> <a href="next?p=2">b</a> <a href="file:///etc/passwd">c</a> <a href="javascript:alert(1)">d</a> <a href="-x">e</a>`, "https://a.com/blog/post", 80)
+		is.Err(t, err, nil)
+		is.Equal(t, len(doc.Links), 3)
+		is.Equal(t, doc.Links[0].URL, "https://a.com/about")
+		is.Equal(t, doc.Links[1].URL, "https://a.com/blog/next?p=2")
+		is.Equal(t, doc.Links[2].URL, "https://a.com/blog/-x")
+
+		doc = render(t, `<a href="/about">a</a> <a href="magnet:?xt=1">b</a>`, 80)
+		is.Equal(t, len(doc.Links), 0)
+	})
+
+	t.Run("skips scripts and styles", func(t *testing.T) {
+		doc := render(t, "<style>p{}</style><script>alert(1)</script><p>text</p>", 80)
+		is.Equal(t, doc.Text, "text")
+	})
+}
+
+func TestSanitize(t *testing.T) {
+	is.Equal(t, Sanitize("a\x1b]52;c;aGk=\x07b\tc\nd\u009be"), "a]52;c;aGk=b c de")
+	is.Equal(t, Sanitize("plain, ünicode"), "plain, ünicode")
+
+	t.Run("article", func(t *testing.T) {
+		doc, err := Render("<p>x\x1b]0;title\x07y</p><pre>a\x1b[2Jb</pre><a href=\"https://a.com\">l\x1bk</a>", "", 80)
+		is.Err(t, err, nil)
+		is.Equal(t, strings.Contains(doc.Text, "\x1b]"), false)
+		is.Equal(t, strings.Contains(doc.Text, "\x07"), false)
+		is.Equal(t, strings.Contains(doc.Text, "\x1b[2J"), false)
+		is.Equal(t, doc.Links[0].Text, "lk")
+	})
+}
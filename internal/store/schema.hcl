@@ -24,6 +24,49 @@ table "reader" {
   }
 }
 
+table "session" {
+  schema = schema.main
+  column "id" {
+    null           = true
+    type           = integer
+    auto_increment = true
+  }
+  column "node_id" {
+    null = false
+    type = text
+  }
+  column "article_id" {
+    null = false
+    type = text
+  }
+  column "reader_open" {
+    null    = false
+    type    = boolean
+    default = 0
+  }
+  column "scroll" {
+    null    = false
+    type    = integer
+    default = 0
+  }
+  column "query" {
+    null = false
+    type = text
+  }
+  column "author" {
+    null = false
+    type = text
+  }
+  column "oldest_first" {
+    null    = false
+    type    = boolean
+    default = 0
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+
 table "folders" {
   schema = schema.main
   column "id" {
@@ -183,6 +226,30 @@ table "article_statuses" {
   }
 }
 
+table "article_labels" {
+  schema = schema.main
+  column "article_id" {
+    null = false
+    type = text
+  }
+  column "label_id" {
+    null = false
+    type = text
+  }
+  primary_key {
+    columns = [column.article_id, column.label_id]
+  }
+  foreign_key "0" {
+    columns     = [column.article_id]
+    ref_columns = [table.articles.column.id]
+    on_update   = NO_ACTION
+    on_delete   = CASCADE
+  }
+  index "idx_article_labels_by_label" {
+    columns = [column.label_id]
+  }
+}
+
 table "pending_actions" {
   schema = schema.main
   column "id" {
@@ -198,6 +265,10 @@ table "pending_actions" {
     null = false
     type = text
   }
+  column "label_id" { // only for label and unlabel
+    null = true
+    type = text
+  }
   column "created_at" {
     null    = false
     type    = integer
@@ -219,6 +290,76 @@ table "pending_actions" {
     columns = [column.article_id]
   }
   check {
-    expr = "(action IN ('read', 'unread', 'star', 'unstar'))"
+    expr = "(action IN ('read', 'unread', 'star', 'unstar', 'label', 'unlabel'))"
+  }
+}
+
+table "pending_mark_all_read" {
+  schema = schema.main
+  column "id" {
+    null           = false
+    type           = integer
+    auto_increment = true
+  }
+  column "stream_id" {
+    null = false
+    type = text
+  }
+  column "older_than" { // timestamp_usec
+    null = false
+    type = integer
+  }
+  column "created_at" {
+    null    = false
+    type    = integer
+    default = sql("strftime('%s', 'now')")
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+
+table "undo_journal" {
+  schema = schema.main
+  column "id" {
+    null           = false
+    type           = integer
+    auto_increment = true
+  }
+  column "change_id" {
+    null = false
+    type = integer
+  }
+  column "article_id" {
+    null = false
+    type = text
+  }
+  column "action" {
+    null = false
+    type = text
+  }
+  column "prev" { // the status, or whether the article had the label, before the change
+    null = false
+    type = boolean
+  }
+  column "label_id" { // set for label changes
+    null = true
+    type = text
+  }
+  column "pending_action_id" { // might be already flushed, 0 if it replaced a queued one
+    null = false
+    type = integer
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "0" {
+    columns     = [column.article_id]
+    ref_columns = [table.articles.column.id]
+    on_update   = NO_ACTION
+    on_delete   = CASCADE
+  }
+  index "idx_undo_journal_change_id" {
+    columns = [column.change_id]
   }
 }
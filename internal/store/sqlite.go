@@ -21,7 +21,9 @@ type Sqlite struct {
 }
 
 func NewSQLite(path string) (*Sqlite, error) {
-	db, err := sql.Open("sqlite", path)
+	// pragmas are per connection, the dsn applies them to every one in the pool,
+	// deletes rely on foreign keys to cascade
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)")
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +35,14 @@ func NewSQLite(path string) (*Sqlite, error) {
 func (s *Sqlite) Close() error { return s.db.Close() }
 
 func (s *Sqlite) Migrate(ctx context.Context) error {
-	driver, err := asqlite.Open(s.db)
+	// one connection, so pragmas the migration sets apply to all of its statements
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	driver, err := asqlite.Open(conn)
 	if err != nil {
 		return err
 	}
@@ -43,7 +52,9 @@ func (s *Sqlite) Migrate(ctx context.Context) error {
 		return err
 	}
 
-	got, err := driver.InspectSchema(ctx, "", nil)
+	got, err := driver.InspectSchema(ctx, "", &aschema.InspectOptions{
+		Exclude: []string{ftsTable + "*"},
+	})
 	if err != nil {
 		return err
 	}
@@ -58,7 +69,5 @@ func (s *Sqlite) Migrate(ctx context.Context) error {
 		return merr
 	}
 
-	_, err = driver.ExecContext(ctx, `--sql
-		PRAGMA foreign_keys = ON`)
-	return err
+	return s.migrateSearch(ctx)
 }
@@ -2,6 +2,8 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -17,9 +19,20 @@ func (s *Sqlite) UpsertArticle(
 	}
 	defer tx.Rollback()
 
-	if _, err = tx.ExecContext(ctx,
+	res, err := tx.ExecContext(ctx,
 		`insert or ignore into articles (id, feed_id, title, content, author, href, published_at) values (?, ?, ?, ?, ?, ?, ?)`,
-		timestampUsec, feedID, title, content, author, href, publishedAt); err != nil {
+		timestampUsec, feedID, title, content, author, href, publishedAt)
+	if err != nil {
+		return err
+	}
+
+	if n, _ := res.RowsAffected(); n > 0 {
+		if err = indexArticle(ctx, tx, timestampUsec, title, content, author); err != nil {
+			return err
+		}
+	} else if _, err = tx.ExecContext(ctx,
+		// older versions stored url of the feed's site instead
+		`update articles set href = ? where id = ?`, href, timestampUsec); err != nil {
 		return err
 	}
 
@@ -30,32 +43,253 @@ func (s *Sqlite) UpsertArticle(
 	return tx.Commit()
 }
 
+// SyncReadStatus marks articles with ids as unread, and the rest as read.
+// Articles with read changes that aren't pushed yet are left as they are,
+// as well as read ones, while mark all as read isn't pushed.
 func (s *Sqlite) SyncReadStatus(ctx context.Context, ids []string) error {
-	placeholders, args := buildPlaceholdersAndArgs(ids)
+	placeholders, args := syncPlaceholders(ids)
 	query := fmt.Sprintf(`--sql
 	update article_statuses
 	set is_read = case when article_id in (%s)
 		then false
 		else true
-	end`, placeholders)
+	end
+	where article_id not in (select article_id from pending_actions where action in ('read', 'unread'))
+	  and not (is_read and exists (select 1 from pending_mark_all_read))`, placeholders)
 
 	_, err := s.db.ExecContext(ctx, query, args...)
 	return err
 }
 
+// SyncStarredStatus stars articles with ids, and unstars the rest.
+// Articles with star changes that aren't pushed yet are left as they are.
 func (s *Sqlite) SyncStarredStatus(ctx context.Context, ids []string) error {
-	placeholders, args := buildPlaceholdersAndArgs(ids)
+	placeholders, args := syncPlaceholders(ids)
 	query := fmt.Sprintf(`--sql
 	update article_statuses
 	set is_starred = case when article_id in (%s)
 		then true
 		else false
-	end`, placeholders)
+	end
+	where article_id not in (select article_id from pending_actions where action in ('star', 'unstar'))`, placeholders)
 
 	_, err := s.db.ExecContext(ctx, query, args...)
 	return err
 }
 
+// syncPlaceholders is [buildPlaceholdersAndArgs], that allows no ids,
+// the server has none of them, e.g. everything is read.
+func syncPlaceholders(ids []string) (string, []any) {
+	if len(ids) == 0 {
+		return "null", nil
+	}
+	return buildPlaceholdersAndArgs(ids)
+}
+
+type Article struct {
+	ID          string
+	FeedID      string
+	FeedTitle   string
+	Title       string
+	Content     string
+	Author      string
+	Href        string
+	PublishedAt int64
+	IsRead      bool
+	IsStarred   bool
+
+	// Labels are ids of user labels of the article
+	Labels []string
+}
+
+// Cursor points to the last article of a page, articles are ordered by
+// publish time and id, both descending, or ascending for [ArticlesFilter.OldestFirst].
+type Cursor struct {
+	PublishedAt int64
+	ID          string
+}
+
+func (a Article) Cursor() Cursor {
+	return Cursor{PublishedAt: a.PublishedAt, ID: a.ID}
+}
+
+type ArticlesFilter struct {
+	FeedID string
+
+	// FolderID, if set, only articles of feeds in the folder,
+	// or labeled with it, are returned.
+	FolderID string
+
+	UnreadOnly  bool
+	StarredOnly bool
+
+	// PublishedAfter, if set, only articles published at or after
+	// the unix time are returned.
+	PublishedAfter int64
+
+	// Query, if set, only articles with it in the title, author
+	// or feed title are returned, case insensitive.
+	Query string
+
+	// Author, if set, only articles with it in the author
+	// are returned, case insensitive.
+	Author string
+
+	// OldestFirst reverses the order of articles.
+	OldestFirst bool
+
+	// After, if set, only articles past the cursor are returned,
+	// older ones, or newer ones for OldestFirst.
+	After *Cursor
+
+	// Limit is the page size, defaults to 100.
+	Limit int
+}
+
+// GetArticles returns a page of articles matching the filter, newest first,
+// unless [ArticlesFilter.OldestFirst] is set.
+// Content is not loaded, use [Sqlite.GetArticle] for that.
+// The returned cursor is nil if there are no more pages.
+func (s *Sqlite) GetArticles(ctx context.Context, filter ArticlesFilter) ([]Article, *Cursor, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+
+	order := "desc"
+	if filter.OldestFirst {
+		order = "asc"
+	}
+
+	where, args := filter.where()
+	query := `--sql
+	select a.id, a.feed_id, f.title, a.title,
+		coalesce(a.author, ''), coalesce(a.href, ''), coalesce(a.published_at, 0),
+		s.is_read, s.is_starred,
+		coalesce((select group_concat(label_id, char(10)) from article_labels where article_id = a.id), '')
+	from articles a
+	join feeds f on f.id = a.feed_id
+	join article_statuses s on s.article_id = a.id
+	where ` + where + `
+	order by coalesce(a.published_at, 0) ` + order + `, a.id ` + order + `
+	limit ?`
+
+	// fetch one more article to find out if there's a next page
+	rows, err := s.db.QueryContext(ctx, query, append(args, filter.Limit+1)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var res []Article
+	for rows.Next() {
+		var a Article
+		var labels string
+		if serr := rows.Scan(&a.ID, &a.FeedID, &a.FeedTitle, &a.Title,
+			&a.Author, &a.Href, &a.PublishedAt,
+			&a.IsRead, &a.IsStarred, &labels); serr != nil {
+			return nil, nil, serr
+		}
+		a.Labels = splitLabels(labels)
+		res = append(res, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(res) <= filter.Limit {
+		return res, nil, nil
+	}
+
+	res = res[:filter.Limit]
+	next := res[len(res)-1].Cursor()
+	return res, &next, nil
+}
+
+// CountArticles returns number of articles matching the filter,
+// pagination options are ignored.
+func (s *Sqlite) CountArticles(ctx context.Context, filter ArticlesFilter) (int, error) {
+	filter.After = nil
+	where, args := filter.where()
+
+	var n int
+	err := s.db.QueryRowContext(ctx, `--sql
+	select count(*)
+	from articles a
+	join article_statuses s on s.article_id = a.id
+	where `+where, args...).Scan(&n)
+	return n, err
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (f ArticlesFilter) where() (string, []any) {
+	conds := []string{"1 = 1"}
+	var args []any
+
+	if f.FeedID != "" {
+		conds = append(conds, "a.feed_id = ?")
+		args = append(args, f.FeedID)
+	}
+	if f.FolderID != "" {
+		conds = append(conds, `(a.feed_id in (select feed_id from feed_folders where folder_id = ?)
+			or a.id in (select article_id from article_labels where label_id = ?))`)
+		args = append(args, f.FolderID, f.FolderID)
+	}
+	if f.UnreadOnly {
+		conds = append(conds, "s.is_read = 0")
+	}
+	if f.StarredOnly {
+		conds = append(conds, "s.is_starred = 1")
+	}
+	if f.PublishedAfter != 0 {
+		conds = append(conds, "coalesce(a.published_at, 0) >= ?")
+		args = append(args, f.PublishedAfter)
+	}
+	if f.Query != "" {
+		q := "%" + likeEscaper.Replace(f.Query) + "%"
+		conds = append(conds, `(a.title like ? escape '\' or a.author like ? escape '\'
+			or a.feed_id in (select id from feeds where title like ? escape '\'))`)
+		args = append(args, q, q, q)
+	}
+	if f.Author != "" {
+		conds = append(conds, `a.author like ? escape '\'`)
+		args = append(args, "%"+likeEscaper.Replace(f.Author)+"%")
+	}
+	if f.After != nil {
+		cmp := "<"
+		if f.OldestFirst {
+			cmp = ">"
+		}
+		conds = append(conds, "(coalesce(a.published_at, 0) "+cmp+" ? or (coalesce(a.published_at, 0) = ? and a.id "+cmp+" ?))")
+		args = append(args, f.After.PublishedAt, f.After.PublishedAt, f.After.ID)
+	}
+
+	return strings.Join(conds, " and "), args
+}
+
+func (s *Sqlite) GetArticle(ctx context.Context, id string) (Article, error) {
+	var a Article
+	var labels string
+	err := s.db.QueryRowContext(ctx, `--sql
+	select a.id, a.feed_id, f.title, a.title,
+		coalesce(a.content, ''), coalesce(a.author, ''), coalesce(a.href, ''),
+		coalesce(a.published_at, 0), s.is_read, s.is_starred,
+		coalesce((select group_concat(label_id, char(10)) from article_labels where article_id = a.id), '')
+	from articles a
+	join feeds f on f.id = a.feed_id
+	join article_statuses s on s.article_id = a.id
+	where a.id = ?`, id).
+		Scan(&a.ID, &a.FeedID, &a.FeedTitle, &a.Title,
+			&a.Content, &a.Author, &a.Href,
+			&a.PublishedAt, &a.IsRead, &a.IsStarred, &labels)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Article{}, ErrNotFound
+	}
+	a.Labels = splitLabels(labels)
+	return a, err
+}
+
 func buildPlaceholdersAndArgs(in []string, prefixArgs ...any) (placeholders string, args []any) {
 	placeholders = strings.Repeat("?,", len(in))
 	placeholders = placeholders[:len(placeholders)-1] // trim trailing comma
@@ -0,0 +1,255 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+// migratedDB is a migrated database, every test starts with a copy of it,
+// since migrating takes most of the time of a test.
+var migratedDB string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "smutok-store")
+	if err != nil {
+		panic(err)
+	}
+	migratedDB = filepath.Join(dir, "smutok.sqlite")
+	if err := migrate(migratedDB); err != nil {
+		os.RemoveAll(dir)
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func migrate(path string) error {
+	db, err := NewSQLite(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Migrate(context.Background())
+}
+
+func newTestStore(t *testing.T) *Sqlite {
+	t.Helper()
+
+	data, err := os.ReadFile(migratedDB)
+	is.Err(t, err, nil)
+	path := filepath.Join(t.TempDir(), "smutok.sqlite")
+	is.Err(t, os.WriteFile(path, data, 0o600), nil)
+
+	db, err := NewSQLite(path)
+	is.Err(t, err, nil)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func seedArticles(t *testing.T, db *Sqlite) {
+	t.Helper()
+	ctx := t.Context()
+
+	is.Err(t, db.UpsertSubscription(ctx, "feed/1", "first", "https://a.com/rss", "https://a.com"), nil)
+	is.Err(t, db.UpsertSubscription(ctx, "feed/2", "second", "https://b.com/rss", "https://b.com"), nil)
+	is.Err(t, db.UpsertTag(ctx, "user/-/label/tech"), nil)
+	is.Err(t, db.LinkFeedWithFolder(ctx, "feed/2", "user/-/label/tech"), nil)
+
+	for _, a := range []struct {
+		id, feedID  string
+		publishedAt int
+	}{
+		{"1", "feed/1", 100},
+		{"2", "feed/1", 200},
+		{"3", "feed/2", 200},
+		{"4", "feed/2", 300},
+		{"5", "feed/1", 400},
+	} {
+		is.Err(t, db.UpsertArticle(ctx, a.id, a.feedID, "title "+a.id, "content", "", "", a.publishedAt), nil)
+	}
+}
+
+func articleIDs(articles []Article) string {
+	ids := make([]string, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestGetArticles(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	t.Run("paginates", func(t *testing.T) {
+		page, next, err := db.GetArticles(ctx, ArticlesFilter{Limit: 2})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "5,4")
+
+		page, next, err = db.GetArticles(ctx, ArticlesFilter{Limit: 2, After: next})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "3,2")
+
+		page, next, err = db.GetArticles(ctx, ArticlesFilter{Limit: 2, After: next})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "1")
+		is.Equal(t, next == nil, true)
+	})
+
+	t.Run("oldest first", func(t *testing.T) {
+		page, next, err := db.GetArticles(ctx, ArticlesFilter{Limit: 2, OldestFirst: true})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "1,2")
+
+		page, _, err = db.GetArticles(ctx, ArticlesFilter{Limit: 2, OldestFirst: true, After: next})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "3,4")
+	})
+
+	t.Run("by feed", func(t *testing.T) {
+		page, _, err := db.GetArticles(ctx, ArticlesFilter{FeedID: "feed/1"})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "5,2,1")
+	})
+
+	t.Run("by folder", func(t *testing.T) {
+		page, _, err := db.GetArticles(ctx, ArticlesFilter{FolderID: "user/-/label/tech"})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "4,3")
+	})
+
+	t.Run("unread and starred", func(t *testing.T) {
+		is.Err(t, db.ChangeArticleStatus(ctx, "4", Read), nil)
+		is.Err(t, db.ChangeArticleStatus(ctx, "2", Star), nil)
+
+		page, _, err := db.GetArticles(ctx, ArticlesFilter{UnreadOnly: true})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "5,3,2,1")
+
+		page, _, err = db.GetArticles(ctx, ArticlesFilter{StarredOnly: true})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "2")
+	})
+
+	t.Run("published after", func(t *testing.T) {
+		page, _, err := db.GetArticles(ctx, ArticlesFilter{PublishedAfter: 300})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "5,4")
+	})
+
+	t.Run("query", func(t *testing.T) {
+		page, _, err := db.GetArticles(ctx, ArticlesFilter{Query: "TITLE 5"})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "5")
+
+		page, _, err = db.GetArticles(ctx, ArticlesFilter{Query: "second"})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "4,3")
+
+		page, _, err = db.GetArticles(ctx, ArticlesFilter{Query: "%"})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(page), "")
+	})
+}
+
+func TestGetArticlesByAuthor(t *testing.T) {
+	db := newTestStore(t)
+	seedSearch(t, db)
+	ctx := t.Context()
+
+	page, _, err := db.GetArticles(ctx, ArticlesFilter{Author: "ro"})
+	is.Err(t, err, nil)
+	is.Equal(t, articleIDs(page), "3")
+
+	page, _, err = db.GetArticles(ctx, ArticlesFilter{Author: "ian", Query: "generics"})
+	is.Err(t, err, nil)
+	is.Equal(t, articleIDs(page), "1")
+}
+
+func TestCountArticles(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.ChangeArticleStatus(ctx, "5", Read), nil)
+
+	n, err := db.CountArticles(ctx, ArticlesFilter{UnreadOnly: true})
+	is.Err(t, err, nil)
+	is.Equal(t, n, 4)
+
+	n, err = db.CountArticles(ctx, ArticlesFilter{FeedID: "feed/1", PublishedAfter: 200})
+	is.Err(t, err, nil)
+	is.Equal(t, n, 2)
+}
+
+func TestGetArticle(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+
+	a, err := db.GetArticle(t.Context(), "3")
+	is.Err(t, err, nil)
+	is.Equal(t, a.FeedTitle, "second")
+	is.Equal(t, a.Content, "content")
+
+	_, err = db.GetArticle(t.Context(), "404")
+	is.Err(t, err, ErrNotFound)
+}
+
+func TestUpsertArticleUpdatesHref(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.UpsertArticle(ctx, "3", "feed/2", "title 3", "content", "", "https://b.com/3", 200), nil)
+	a, err := db.GetArticle(ctx, "3")
+	is.Err(t, err, nil)
+	is.Equal(t, a.Href, "https://b.com/3")
+}
+
+func TestSyncStatuses(t *testing.T) {
+	unreadIDs := func(t *testing.T, db *Sqlite) string {
+		page, _, err := db.GetArticles(t.Context(), ArticlesFilter{UnreadOnly: true})
+		is.Err(t, err, nil)
+		return articleIDs(page)
+	}
+
+	t.Run("keeps pending changes", func(t *testing.T) {
+		db := newTestStore(t)
+		seedArticles(t, db)
+		ctx := t.Context()
+
+		is.Err(t, db.ChangeArticleStatus(ctx, "1", Read), nil)
+		is.Err(t, db.ChangeArticleStatus(ctx, "2", Star), nil)
+
+		is.Err(t, db.SyncReadStatus(ctx, []string{"1", "2", "3"}), nil)
+		is.Equal(t, unreadIDs(t, db), "3,2")
+
+		is.Err(t, db.SyncStarredStatus(ctx, nil), nil)
+		starred, _, err := db.GetArticles(ctx, ArticlesFilter{StarredOnly: true})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(starred), "2")
+
+		is.Err(t, db.SyncReadStatus(ctx, nil), nil)
+		is.Equal(t, unreadIDs(t, db), "")
+	})
+
+	t.Run("keeps pending mark all as read", func(t *testing.T) {
+		db := newTestStore(t)
+		seedArticles(t, db)
+		ctx := t.Context()
+
+		_, err := db.MarkAllAsRead(ctx, ArticlesFilter{FeedID: "feed/1"}, "feed/1", 10)
+		is.Err(t, err, nil)
+		is.Err(t, db.SyncReadStatus(ctx, []string{"1", "2", "3", "4", "5"}), nil)
+		is.Equal(t, unreadIDs(t, db), "4,3")
+	})
+}
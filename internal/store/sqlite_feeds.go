@@ -7,12 +7,25 @@ import (
 
 func (s *Sqlite) UpsertSubscription(ctx context.Context, id, title, url, htmlURL string) error {
 	_, err := s.db.ExecContext(ctx,
-		`insert or ignore into feeds (id, title, url, htmlUrl)
-		values (?, ?, ?, ?)`,
+		`insert into feeds (id, title, url, htmlUrl)
+		values (?, ?, ?, ?)
+		on conflict(id) do update set
+			title = excluded.title, url = excluded.url, htmlUrl = excluded.htmlUrl`,
 		id, title, url, htmlURL)
 	return err
 }
 
+func (s *Sqlite) RenameFeed(ctx context.Context, id, title string) error {
+	res, err := s.db.ExecContext(ctx, `update feeds set title = ? where id = ?`, title, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 func (s *Sqlite) LinkFeedWithFolder(ctx context.Context, feedID, folderID string) error {
 	_, err := s.db.ExecContext(ctx,
 		`insert or ignore into feed_folders (feed_id, folder_id)
@@ -22,12 +35,139 @@ func (s *Sqlite) LinkFeedWithFolder(ctx context.Context, feedID, folderID string
 }
 
 func (s *Sqlite) RemoveNonExistentFeeds(ctx context.Context, currentFeedIDs []string) error {
-	placeholders, args := buildPlaceholdersAndArgs(currentFeedIDs)
-	query := fmt.Sprintf(`--sql
+	// the server has no subscriptions, every feed is removed
+	removed := "true"
+	var args []any
+	if len(currentFeedIDs) > 0 {
+		var placeholders string
+		placeholders, args = buildPlaceholdersAndArgs(currentFeedIDs)
+		removed = "id NOT IN (" + placeholders + ")"
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// articles are deleted by the cascade, but the search index isn't
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`--sql
+	DELETE FROM articles_fts
+	WHERE article_id IN (SELECT id FROM articles WHERE feed_id IN (SELECT id FROM feeds WHERE %s))
+	`, removed), args...); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`--sql
 	DELETE FROM feeds
-	WHERE id NOT IN (%s)
-	`, placeholders)
+	WHERE %s
+	`, removed), args...); err != nil {
+		return err
+	}
 
-	_, err := s.db.ExecContext(ctx, query, args...)
-	return err
+	return tx.Commit()
+}
+
+type Feed struct {
+	ID          string
+	Title       string
+	URL         string
+	HTMLURL     string
+	UnreadCount int
+}
+
+func (s *Sqlite) GetFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := s.db.QueryContext(ctx, `--sql
+	select f.id, f.title, f.url, f.htmlUrl,
+		count(s.article_id) filter (where s.is_read = 0)
+	from feeds f
+	left join articles a on a.feed_id = f.id
+	left join article_statuses s on s.article_id = a.id
+	group by f.id
+	order by f.title collate nocase`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []Feed
+	for rows.Next() {
+		var f Feed
+		if serr := rows.Scan(&f.ID, &f.Title, &f.URL, &f.HTMLURL, &f.UnreadCount); serr != nil {
+			return res, serr
+		}
+		res = append(res, f)
+	}
+
+	return res, rows.Err()
+}
+
+// DeleteFeed deletes the feed with its articles.
+func (s *Sqlite) DeleteFeed(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`delete from articles_fts where article_id in (select id from articles where feed_id = ?)`, id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `delete from feeds where id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+// GetFeedFolders returns ids of folders the feed is in.
+func (s *Sqlite) GetFeedFolders(ctx context.Context, feedID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `select folder_id from feed_folders where feed_id = ?`, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var id string
+		if serr := rows.Scan(&id); serr != nil {
+			return res, serr
+		}
+		res = append(res, id)
+	}
+
+	return res, rows.Err()
+}
+
+// SetFeedFolders replaces folders of the feed, creating them if needed.
+// Without folders the feed is removed from all of them.
+func (s *Sqlite) SetFeedFolders(ctx context.Context, feedID string, folderIDs ...string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `delete from feed_folders where feed_id = ?`, feedID); err != nil {
+		return err
+	}
+
+	for _, folderID := range folderIDs {
+		if _, err := tx.ExecContext(ctx, `insert or ignore into folders (id) values (?)`, folderID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`insert or ignore into feed_folders (feed_id, folder_id) values (?, ?)`, feedID, folderID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestDeleteFeed(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.DeleteFeed(ctx, "feed/1"), nil)
+	is.Err(t, db.DeleteFeed(ctx, "feed/1"), ErrNotFound)
+
+	page, _, err := db.GetArticles(ctx, ArticlesFilter{})
+	is.Err(t, err, nil)
+	is.Equal(t, articleIDs(page), "4,3")
+
+	res, err := db.Search(ctx, "title", 10)
+	is.Err(t, err, nil)
+	is.Equal(t, len(res), 2)
+}
+
+func TestRemoveNonExistentFeeds(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.RemoveNonExistentFeeds(ctx, []string{"feed/2"}), nil)
+	feeds, err := db.GetFeeds(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, len(feeds), 1)
+
+	is.Err(t, db.RemoveNonExistentFeeds(ctx, nil), nil)
+	feeds, err = db.GetFeeds(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, len(feeds), 0)
+
+	res, err := db.Search(ctx, "title", 10)
+	is.Err(t, err, nil)
+	is.Equal(t, len(res), 0)
+}
+
+func TestSetFeedFolders(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.SetFeedFolders(ctx, "feed/2", "user/-/label/news"), nil)
+	folders, err := db.GetFeedFolders(ctx, "feed/2")
+	is.Err(t, err, nil)
+	is.Equal(t, strings.Join(folders, ","), "user/-/label/news")
+
+	is.Err(t, db.SetFeedFolders(ctx, "feed/2"), nil)
+	folders, err = db.GetFeedFolders(ctx, "feed/2")
+	is.Err(t, err, nil)
+	is.Equal(t, len(folders), 0)
+}
+
+func TestUpsertSubscription(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.RenameFeed(ctx, "feed/1", "renamed"), nil)
+	is.Err(t, db.RenameFeed(ctx, "feed/404", "renamed"), ErrNotFound)
+
+	feeds, err := db.GetFeeds(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, feeds[0].Title, "renamed")
+
+	// sync brings the title from the server back, keeping the articles
+	is.Err(t, db.UpsertSubscription(ctx, "feed/1", "first", "https://a.com/rss", "https://a.com"), nil)
+	feeds, err = db.GetFeeds(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, feeds[0].Title, "first")
+	is.Equal(t, feeds[0].UnreadCount, 3)
+}
+
+func TestForeignKeysOnEveryConnection(t *testing.T) {
+	db := newTestStore(t)
+	ctx := t.Context()
+
+	// hold connections at once, so the pool has to open new ones
+	for range 3 {
+		conn, err := db.db.Conn(ctx)
+		is.Err(t, err, nil)
+		defer conn.Close()
+
+		var on int
+		is.Err(t, conn.QueryRowContext(ctx, `pragma foreign_keys`).Scan(&on), nil)
+		is.Equal(t, on, 1)
+	}
+}
@@ -1,8 +1,175 @@
 package store
 
-import "context"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const labelPrefix = "user/-/label/"
 
 func (s *Sqlite) UpsertTag(ctx context.Context, id string) error {
 	_, err := s.db.ExecContext(ctx, `insert or replace into folders (id) values (?)`, id)
 	return err
 }
+
+type Folder struct {
+	ID          string
+	Name        string
+	FeedIDs     []string
+	UnreadCount int
+}
+
+// GetFolders returns all user labels with ids of feeds that are linked to them,
+// and number of unread articles in them, or labeled with them.
+func (s *Sqlite) GetFolders(ctx context.Context) ([]Folder, error) {
+	rows, err := s.db.QueryContext(ctx, `--sql
+	select f.id, ff.feed_id,
+		(select count(*)
+		 from articles a
+		 join article_statuses s on s.article_id = a.id
+		 where s.is_read = 0
+		   and (a.feed_id in (select feed_id from feed_folders where folder_id = f.id)
+		     or a.id in (select article_id from article_labels where label_id = f.id)))
+	from folders f
+	left join feed_folders ff on ff.folder_id = f.id
+	where f.id like ?
+	order by f.id collate nocase`, labelPrefix+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []Folder
+	for rows.Next() {
+		var id string
+		var feedID sql.NullString
+		var unread int
+		if serr := rows.Scan(&id, &feedID, &unread); serr != nil {
+			return res, serr
+		}
+
+		if len(res) == 0 || res[len(res)-1].ID != id {
+			res = append(res, Folder{
+				ID:          id,
+				Name:        strings.TrimPrefix(id, labelPrefix),
+				UnreadCount: unread,
+			})
+		}
+		if feedID.Valid {
+			res[len(res)-1].FeedIDs = append(res[len(res)-1].FeedIDs, feedID.String)
+		}
+	}
+
+	return res, rows.Err()
+}
+
+// RenameFolder moves feeds and labeled articles of the folder to the new one,
+// merging them if it already exists, and deletes the old folder.
+// Queued label changes are moved to the new folder as well.
+func (s *Sqlite) RenameFolder(ctx context.Context, id, newID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `insert or ignore into folders (id) values (?)`, newID); err != nil {
+		return err
+	}
+
+	// links that are already in the new folder are left behind, and deleted by the cascade
+	if _, err := tx.ExecContext(ctx,
+		`update or ignore feed_folders set folder_id = ? where folder_id = ?`, newID, id); err != nil {
+		return err
+	}
+
+	// only the latest change of the label is pushed, if both folders had one
+	if _, err := tx.ExecContext(ctx, `--sql
+	delete from pending_actions
+	where label_id in (?, ?)
+	  and id not in (
+	    select max(id) from pending_actions
+	    where label_id in (?, ?)
+	    group by article_id
+	  )`, id, newID, id, newID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`update pending_actions set label_id = ? where label_id = ?`, newID, id); err != nil {
+		return err
+	}
+
+	// labeled articles are moved the same way as feeds, but leftovers are deleted by hand
+	if _, err := tx.ExecContext(ctx,
+		`update or ignore article_labels set label_id = ? where label_id = ?`, newID, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `delete from article_labels where label_id = ?`, id); err != nil {
+		return err
+	}
+
+	// and the latest change wins over the merged label
+	if _, err := tx.ExecContext(ctx, `--sql
+	delete from article_labels
+	where label_id = ?
+	  and article_id in (select article_id from pending_actions where label_id = ? and action = 'unlabel')`,
+		newID, newID); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `delete from folders where id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+// DeleteFolder deletes the folder, its feeds are kept outside of any folder,
+// and articles lose the label, queued changes of it are dropped.
+func (s *Sqlite) DeleteFolder(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `delete from article_labels where label_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `delete from pending_actions where label_id = ?`, id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `delete from folders where id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+// RemoveNonExistentFolders deletes user labels that aren't in currentIDs,
+// except ones with queued changes, they don't exist on the server until pushed.
+func (s *Sqlite) RemoveNonExistentFolders(ctx context.Context, currentIDs []string) error {
+	const pending = `id NOT IN (SELECT label_id FROM pending_actions WHERE label_id IS NOT NULL)`
+	if len(currentIDs) == 0 {
+		_, err := s.db.ExecContext(ctx, `delete from folders where id like ? and `+pending, labelPrefix+"%")
+		return err
+	}
+
+	placeholders, args := buildPlaceholdersAndArgs(currentIDs, labelPrefix+"%")
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`--sql
+	DELETE FROM folders
+	WHERE id LIKE ? AND id NOT IN (%s) AND %s
+	`, placeholders, pending), args...)
+	return err
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func folderNames(t *testing.T, db *Sqlite) string {
+	t.Helper()
+	folders, err := db.GetFolders(t.Context())
+	is.Err(t, err, nil)
+
+	res := make([]string, len(folders))
+	for i, f := range folders {
+		res[i] = f.Name + ":" + strings.Join(f.FeedIDs, "+")
+	}
+	return strings.Join(res, ",")
+}
+
+func TestRenameFolder(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+	is.Err(t, db.AddArticlesLabel(ctx, []string{"1"}, "user/-/label/tech"), nil)
+
+	is.Err(t, db.RenameFolder(ctx, "user/-/label/tech", "user/-/label/dev"), nil)
+	is.Equal(t, folderNames(t, db), "dev:feed/2")
+	is.Err(t, db.RenameFolder(ctx, "user/-/label/tech", "user/-/label/dev"), ErrNotFound)
+
+	page, _, err := db.GetArticles(ctx, ArticlesFilter{FolderID: "user/-/label/dev"})
+	is.Err(t, err, nil)
+	is.Equal(t, articleIDs(page), "4,3,1")
+	is.Equal(t, strings.Join(page[2].Labels, ","), "user/-/label/dev")
+	is.Equal(t, labelChanges(t, db), "label user/-/label/dev 1")
+
+	// renaming into an existing folder merges them
+	is.Err(t, db.SetFeedFolders(ctx, "feed/1", "user/-/label/news"), nil)
+	is.Err(t, db.SetFeedFolders(ctx, "feed/2", "user/-/label/dev", "user/-/label/news"), nil)
+	is.Err(t, db.RemoveArticlesLabel(ctx, []string{"1"}, "user/-/label/news"), nil)
+	is.Err(t, db.RenameFolder(ctx, "user/-/label/dev", "user/-/label/news"), nil)
+	is.Equal(t, folderNames(t, db), "news:feed/1+feed/2")
+	is.Equal(t, labelChanges(t, db), "unlabel user/-/label/news 1")
+	a, err := db.GetArticle(ctx, "1")
+	is.Err(t, err, nil)
+	is.Equal(t, len(a.Labels), 0)
+}
+
+func TestDeleteFolder(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+	is.Err(t, db.AddArticlesLabel(ctx, []string{"1"}, "user/-/label/tech"), nil)
+
+	is.Err(t, db.DeleteFolder(ctx, "user/-/label/tech"), nil)
+	is.Err(t, db.DeleteFolder(ctx, "user/-/label/tech"), ErrNotFound)
+	is.Equal(t, folderNames(t, db), "")
+
+	a, err := db.GetArticle(ctx, "1")
+	is.Err(t, err, nil)
+	is.Equal(t, len(a.Labels), 0)
+	is.Equal(t, labelChanges(t, db), "")
+
+	folders, err := db.GetFeedFolders(ctx, "feed/2")
+	is.Err(t, err, nil)
+	is.Equal(t, len(folders), 0)
+
+	feeds, err := db.GetFeeds(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, len(feeds), 2)
+}
+
+func TestRemoveNonExistentFolders(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.UpsertTag(ctx, "user/-/label/news"), nil)
+	is.Err(t, db.UpsertTag(ctx, "user/-/state/com.google/starred"), nil)
+
+	is.Err(t, db.RemoveNonExistentFolders(ctx, []string{"user/-/label/news"}), nil)
+	is.Equal(t, folderNames(t, db), "news:")
+
+	is.Err(t, db.RemoveNonExistentFolders(ctx, nil), nil)
+	is.Equal(t, folderNames(t, db), "")
+
+	// labels that aren't pushed yet
+	is.Err(t, db.AddArticlesLabel(ctx, []string{"1"}, "user/-/label/later"), nil)
+	is.Err(t, db.RemoveNonExistentFolders(ctx, nil), nil)
+	is.Equal(t, folderNames(t, db), "later:")
+}
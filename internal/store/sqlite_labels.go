@@ -0,0 +1,200 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AddArticlesLabel adds the label to the articles, and queues the change
+// for every article. If any of the articles doesn't exist, nothing is changed.
+//
+// The change is recorded in the undo journal, see [Sqlite.UndoStatusChange].
+func (s *Sqlite) AddArticlesLabel(ctx context.Context, articleIDs []string, labelID string) error {
+	return s.changeArticlesLabel(ctx, articleIDs, labelID, Label)
+}
+
+// RemoveArticlesLabel removes the label from the articles, and queues the change
+// for every article. If any of the articles doesn't exist, nothing is changed.
+func (s *Sqlite) RemoveArticlesLabel(ctx context.Context, articleIDs []string, labelID string) error {
+	return s.changeArticlesLabel(ctx, articleIDs, labelID, Unlabel)
+}
+
+func (s *Sqlite) changeArticlesLabel(ctx context.Context, articleIDs []string, labelID string, action Action) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	changeID, err := nextChangeID(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// so a new label shows up with the folders
+	if action == Label {
+		if _, err := tx.ExecContext(ctx, `insert or ignore into folders (id) values (?)`, labelID); err != nil {
+			return err
+		}
+	}
+
+	query := `insert or ignore into article_labels (article_id, label_id) values (?, ?)`
+	if action == Unlabel {
+		query = `delete from article_labels where article_id = ? and label_id = ?`
+	}
+
+	for _, id := range articleIDs {
+		var exists bool
+		err := tx.QueryRowContext(ctx, `select 1 from articles where id = ?`, id).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var prev bool
+		if err := tx.QueryRowContext(ctx,
+			`select exists (select 1 from article_labels where article_id = ? and label_id = ?)`,
+			id, labelID).Scan(&prev); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, query, id, labelID); err != nil {
+			return err
+		}
+
+		// only the latest change of the label is pushed
+		res, err := tx.ExecContext(ctx, `--sql
+		delete from pending_actions
+		where article_id = ? and label_id = ? and action in ('label', 'unlabel')`,
+			id, labelID)
+		if err != nil {
+			return err
+		}
+		replaced, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		res, err = tx.ExecContext(ctx,
+			`insert into pending_actions (article_id, action, label_id) values (?, ?, ?)`,
+			id, action.String(), labelID)
+		if err != nil {
+			return err
+		}
+		pendingID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		if replaced > 0 {
+			pendingID = 0
+		}
+
+		if _, err := tx.ExecContext(ctx, `--sql
+		insert into undo_journal (change_id, article_id, action, label_id, prev, pending_action_id)
+		values (?, ?, ?, ?, ?, ?)`,
+			changeID, id, action.String(), labelID, prev, pendingID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetArticleLabels replaces labels of the article with ones from the server.
+// Articles with label changes that aren't pushed yet are left as they are.
+func (s *Sqlite) SetArticleLabels(ctx context.Context, articleID string, labelIDs ...string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var pending int
+	if err := tx.QueryRowContext(ctx, `--sql
+	select count(*) from pending_actions
+	where article_id = ? and action in ('label', 'unlabel')`, articleID).Scan(&pending); err != nil {
+		return err
+	}
+	if pending > 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `delete from article_labels where article_id = ?`, articleID); err != nil {
+		return err
+	}
+
+	for _, labelID := range labelIDs {
+		if _, err := tx.ExecContext(ctx,
+			`insert or ignore into article_labels (article_id, label_id) values (?, ?)`, articleID, labelID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LabelChange is a queued change of a label of articles.
+type LabelChange struct {
+	// Action is either [Label] or [Unlabel]
+	Action     Action
+	LabelID    string
+	ArticleIDs []string
+}
+
+// GetPendingLabelChanges returns queued label changes, grouped by the label and action.
+func (s *Sqlite) GetPendingLabelChanges(ctx context.Context) ([]LabelChange, error) {
+	rows, err := s.db.QueryContext(ctx, `--sql
+	select action, label_id, article_id
+	from pending_actions
+	where action in ('label', 'unlabel')
+	order by label_id, action, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []LabelChange
+	for rows.Next() {
+		var action, labelID, articleID string
+		if serr := rows.Scan(&action, &labelID, &articleID); serr != nil {
+			return res, serr
+		}
+
+		a, err := parseAction(action)
+		if err != nil {
+			return res, err
+		}
+
+		if len(res) == 0 || res[len(res)-1].Action != a || res[len(res)-1].LabelID != labelID {
+			res = append(res, LabelChange{Action: a, LabelID: labelID})
+		}
+		res[len(res)-1].ArticleIDs = append(res[len(res)-1].ArticleIDs, articleID)
+	}
+
+	return res, rows.Err()
+}
+
+// DeletePendingLabelChange deletes the pushed change from the queue.
+func (s *Sqlite) DeletePendingLabelChange(ctx context.Context, change LabelChange) error {
+	placeholders, args := buildPlaceholdersAndArgs(change.ArticleIDs, change.Action.String(), change.LabelID)
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`--sql
+	delete from pending_actions
+	where action = ?
+	  and label_id = ?
+	  and article_id in (%s)
+	`, placeholders), args...)
+	return err
+}
+
+// splitLabels splits labels joined with group_concat(label_id, char(10)).
+func splitLabels(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
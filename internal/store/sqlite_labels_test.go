@@ -0,0 +1,69 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func labelChanges(t *testing.T, db *Sqlite) string {
+	t.Helper()
+	changes, err := db.GetPendingLabelChanges(t.Context())
+	is.Err(t, err, nil)
+
+	res := make([]string, len(changes))
+	for i, c := range changes {
+		res[i] = c.Action.String() + " " + c.LabelID + " " + strings.Join(c.ArticleIDs, "+")
+	}
+	return strings.Join(res, ",")
+}
+
+func TestChangeArticlesLabel(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.AddArticlesLabel(ctx, []string{"1", "2"}, "user/-/label/later"), nil)
+	is.Err(t, db.AddArticlesLabel(ctx, []string{"1", "404"}, "user/-/label/go"), ErrNotFound)
+
+	a, err := db.GetArticle(ctx, "1")
+	is.Err(t, err, nil)
+	is.Equal(t, strings.Join(a.Labels, ","), "user/-/label/later")
+	is.Equal(t, labelChanges(t, db), "label user/-/label/later 1+2")
+
+	// new labels are listed with folders, and filter their articles
+	is.Equal(t, folderNames(t, db), "later:,tech:feed/2")
+	page, _, err := db.GetArticles(ctx, ArticlesFilter{FolderID: "user/-/label/later"})
+	is.Err(t, err, nil)
+	is.Equal(t, articleIDs(page), "2,1")
+	is.Equal(t, strings.Join(page[0].Labels, ","), "user/-/label/later")
+
+	// only the latest change is queued
+	is.Err(t, db.RemoveArticlesLabel(ctx, []string{"2"}, "user/-/label/later"), nil)
+	is.Equal(t, labelChanges(t, db), "label user/-/label/later 1,unlabel user/-/label/later 2")
+
+	changes, err := db.GetPendingLabelChanges(ctx)
+	is.Err(t, err, nil)
+	is.Err(t, db.DeletePendingLabelChange(ctx, changes[0]), nil)
+	is.Equal(t, labelChanges(t, db), "unlabel user/-/label/later 2")
+}
+
+func TestSetArticleLabels(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.SetArticleLabels(ctx, "1", "user/-/label/a", "user/-/label/b"), nil)
+	is.Err(t, db.SetArticleLabels(ctx, "1", "user/-/label/b"), nil)
+	a, err := db.GetArticle(ctx, "1")
+	is.Err(t, err, nil)
+	is.Equal(t, strings.Join(a.Labels, ","), "user/-/label/b")
+
+	// changes that aren't pushed yet win over the server
+	is.Err(t, db.AddArticlesLabel(ctx, []string{"1"}, "user/-/label/c"), nil)
+	is.Err(t, db.SetArticleLabels(ctx, "1"), nil)
+	a, err = db.GetArticle(ctx, "1")
+	is.Err(t, err, nil)
+	is.Equal(t, strings.Join(a.Labels, ","), "user/-/label/b,user/-/label/c")
+}
@@ -2,6 +2,8 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 )
 
@@ -12,6 +14,8 @@ const (
 	Unread
 	Star
 	Unstar
+	Label
+	Unlabel
 )
 
 func (a Action) String() string {
@@ -24,11 +28,45 @@ func (a Action) String() string {
 		return "star"
 	case Unstar:
 		return "unstar"
+	case Label:
+		return "label"
+	case Unlabel:
+		return "unlabel"
 	default:
 		return "unsupported"
 	}
 }
 
+// Opposite returns the action that reverts a.
+func (a Action) Opposite() Action {
+	switch a {
+	case Read:
+		return Unread
+	case Unread:
+		return Read
+	case Star:
+		return Unstar
+	case Unstar:
+		return Star
+	case Label:
+		return Unlabel
+	case Unlabel:
+		return Label
+	default:
+		return a
+	}
+}
+
+// parseAction parses action as it's stored in the database.
+func parseAction(s string) (Action, error) {
+	for _, a := range []Action{Read, Unread, Star, Unstar, Label, Unlabel} {
+		if a.String() == s {
+			return a, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported action: %q", s)
+}
+
 var changeArticleStatusQuery = map[Action]string{
 	Read:   `update article_statuses set is_read = 1 where article_id = ?`,
 	Unread: `update article_statuses set is_read = 0 where article_id = ?`,
@@ -37,25 +75,82 @@ var changeArticleStatusQuery = map[Action]string{
 }
 
 func (s *Sqlite) ChangeArticleStatus(ctx context.Context, articleID string, action Action) error {
+	return s.ChangeArticlesStatus(ctx, []string{articleID}, action)
+}
+
+// ChangeArticlesStatus applies the action to all articles in one transaction,
+// and queues it for every article. If any of the articles doesn't exist,
+// nothing is changed.
+//
+// The change is recorded in the undo journal, see [Sqlite.UndoStatusChange].
+func (s *Sqlite) ChangeArticlesStatus(ctx context.Context, articleIDs []string, action Action) error {
+	if _, ok := statusColumn[action]; !ok {
+		return fmt.Errorf("not a status action: %s", action)
+	}
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// update article status
-	e, err := tx.ExecContext(ctx, changeArticleStatusQuery[action], articleID)
+	changeID, err := nextChangeID(ctx, tx)
 	if err != nil {
 		return err
 	}
-	if n, _ := e.RowsAffected(); n == 0 {
-		return ErrNotFound
-	}
 
-	// enqueue action
-	if _, err := tx.ExecContext(ctx, `insert into pending_actions (article_id, action) values (?, ?)`,
-		articleID, action.String()); err != nil {
-		return err
+	for _, id := range articleIDs {
+		var prev bool
+		err := tx.QueryRowContext(ctx,
+			`select `+statusColumn[action]+` from article_statuses where article_id = ?`, id).
+			Scan(&prev)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// update article status
+		if _, err := tx.ExecContext(ctx, changeArticleStatusQuery[action], id); err != nil {
+			return err
+		}
+
+		// the opposite change isn't pushed yet, only the latest one is,
+		// since actions are pushed in groups, not in the order they were made
+		res, err := tx.ExecContext(ctx, `delete from pending_actions where article_id = ? and action = ?`,
+			id, action.Opposite().String())
+		if err != nil {
+			return err
+		}
+		replaced, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		// enqueue action
+		res, err = tx.ExecContext(ctx, `insert into pending_actions (article_id, action) values (?, ?)`,
+			id, action.String())
+		if err != nil {
+			return err
+		}
+		pendingID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// dropping the action on undo wouldn't bring the replaced one back,
+		// so it's undone as a pushed one
+		if replaced > 0 {
+			pendingID = 0
+		}
+
+		if _, err := tx.ExecContext(ctx, `--sql
+		insert into undo_journal (change_id, article_id, action, prev, pending_action_id)
+		values (?, ?, ?, ?, ?)`,
+			changeID, id, action.String(), prev, pendingID); err != nil {
+			return err
+		}
 	}
 
 	return tx.Commit()
@@ -106,3 +201,113 @@ func (s *Sqlite) DeletePendingActions(
 	_, err := s.db.ExecContext(ctx, query, args...)
 	return err
 }
+
+func (s *Sqlite) CountPendingActions(ctx context.Context) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, `--sql
+	select (select count(*) from pending_actions) +
+		(select count(*) from pending_mark_all_read)`).Scan(&n)
+	return n, err
+}
+
+// MarkAllRead is a queued "mark all as read" of a stream.
+type MarkAllRead struct {
+	ID       int64
+	StreamID string
+
+	// OlderThan is timestamp_usec, only articles fetched before it are marked.
+	OlderThan int64
+}
+
+// MarkAllAsRead marks unread articles that match the filter, and were fetched
+// before olderThan (timestamp_usec, same as article ids), as read.
+//
+// If streamID is set, one mark-all-as-read of the stream is queued,
+// otherwise every marked article is queued separately.
+// The change is recorded in the undo journal, see [Sqlite.UndoStatusChange].
+// Returns the number of marked articles.
+func (s *Sqlite) MarkAllAsRead(ctx context.Context, filter ArticlesFilter, streamID string, olderThan int64) (int, error) {
+	filter.After = nil
+	filter.UnreadOnly = true
+	where, args := filter.where()
+	args = append(args, olderThan)
+
+	articles := `--sql
+	select a.id
+	from articles a
+	join article_statuses s on s.article_id = a.id
+	where ` + where + ` and cast(a.id as integer) < ?`
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	changeID, err := nextChangeID(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	// the marked articles were unread, the change is undone as a pushed one,
+	// since the queued mark all as read can't be taken back for some articles
+	if _, err := tx.ExecContext(ctx, `--sql
+	insert into undo_journal (change_id, article_id, action, prev, pending_action_id)
+	select ?, id, 'read', 0, 0 from (`+articles+`)`, append([]any{changeID}, args...)...); err != nil {
+		return 0, err
+	}
+
+	// queued changes of the articles are superseded
+	if _, err := tx.ExecContext(ctx, `delete from pending_actions
+		where action in ('read', 'unread') and article_id in (`+articles+`)`, args...); err != nil {
+		return 0, err
+	}
+
+	if streamID == "" {
+		if _, err := tx.ExecContext(ctx, `insert into pending_actions (article_id, action)
+			select id, 'read' from (`+articles+`)`, args...); err != nil {
+			return 0, err
+		}
+	} else {
+		if _, err := tx.ExecContext(ctx, `insert into pending_mark_all_read (stream_id, older_than) values (?, ?)`,
+			streamID, olderThan); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `update article_statuses set is_read = 1
+		where article_id in (`+articles+`)`, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), tx.Commit()
+}
+
+func (s *Sqlite) GetPendingMarkAllRead(ctx context.Context) ([]MarkAllRead, error) {
+	rows, err := s.db.QueryContext(ctx, `select id, stream_id, older_than from pending_mark_all_read order by id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []MarkAllRead
+	for rows.Next() {
+		var m MarkAllRead
+		if serr := rows.Scan(&m.ID, &m.StreamID, &m.OlderThan); serr != nil {
+			return res, serr
+		}
+		res = append(res, m)
+	}
+
+	return res, rows.Err()
+}
+
+func (s *Sqlite) DeletePendingMarkAllRead(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `delete from pending_mark_all_read where id = ?`, id)
+	return err
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestMarkAllAsRead(t *testing.T) {
+	t.Run("stream", func(t *testing.T) {
+		db := newTestStore(t)
+		seedArticles(t, db)
+		ctx := t.Context()
+
+		is.Err(t, db.ChangeArticleStatus(ctx, "5", Unread), nil)
+
+		n, err := db.MarkAllAsRead(ctx, ArticlesFilter{FeedID: "feed/1"}, "feed/1", 5)
+		is.Err(t, err, nil)
+		is.Equal(t, n, 2)
+
+		unread, _, err := db.GetArticles(ctx, ArticlesFilter{UnreadOnly: true})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(unread), "5,4,3")
+
+		queued, err := db.GetPendingMarkAllRead(ctx)
+		is.Err(t, err, nil)
+		is.Equal(t, len(queued), 1)
+		is.Equal(t, queued[0].StreamID, "feed/1")
+		is.Equal(t, queued[0].OlderThan, int64(5))
+
+		// the unread of article 5 is still queued, it's newer than the cutoff
+		pending, err := db.CountPendingActions(ctx)
+		is.Err(t, err, nil)
+		is.Equal(t, pending, 2)
+
+		is.Err(t, db.DeletePendingMarkAllRead(ctx, queued[0].ID), nil)
+		pending, err = db.CountPendingActions(ctx)
+		is.Err(t, err, nil)
+		is.Equal(t, pending, 1)
+	})
+
+	t.Run("per article", func(t *testing.T) {
+		db := newTestStore(t)
+		seedArticles(t, db)
+		ctx := t.Context()
+
+		is.Err(t, db.ChangeArticleStatus(ctx, "3", Unread), nil)
+
+		n, err := db.MarkAllAsRead(ctx, ArticlesFilter{PublishedAfter: 200}, "", 100)
+		is.Err(t, err, nil)
+		is.Equal(t, n, 4)
+
+		ids, err := db.GetPendingActions(ctx, Read)
+		is.Err(t, err, nil)
+		is.Equal(t, len(ids), 4)
+
+		ids, err = db.GetPendingActions(ctx, Unread)
+		is.Err(t, err, nil)
+		is.Equal(t, len(ids), 0)
+	})
+}
+
+func TestChangeArticlesStatus(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	t.Run("queues every article", func(t *testing.T) {
+		is.Err(t, db.ChangeArticlesStatus(ctx, []string{"1", "3", "5"}, Star), nil)
+
+		starred, _, err := db.GetArticles(ctx, ArticlesFilter{StarredOnly: true})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(starred), "5,3,1")
+
+		queued, err := db.GetPendingActions(ctx, Star)
+		is.Err(t, err, nil)
+		is.Equal(t, len(queued), 3)
+	})
+
+	t.Run("missing article rolls back", func(t *testing.T) {
+		is.Err(t, db.ChangeArticlesStatus(ctx, []string{"2", "404"}, Star), ErrNotFound)
+
+		starred, _, err := db.GetArticles(ctx, ArticlesFilter{StarredOnly: true})
+		is.Err(t, err, nil)
+		is.Equal(t, articleIDs(starred), "5,3,1")
+
+		pending, err := db.CountPendingActions(ctx)
+		is.Err(t, err, nil)
+		is.Equal(t, pending, 3)
+	})
+
+	t.Run("replaces the opposite queued action", func(t *testing.T) {
+		is.Err(t, db.ChangeArticlesStatus(ctx, []string{"1"}, Unstar), nil)
+
+		starred, err := db.GetPendingActions(ctx, Star)
+		is.Err(t, err, nil)
+		is.Equal(t, strings.Join(starred, ","), "5,3")
+
+		unstarred, err := db.GetPendingActions(ctx, Unstar)
+		is.Err(t, err, nil)
+		is.Equal(t, strings.Join(unstarred, ","), "1")
+	})
+}
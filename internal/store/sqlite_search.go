@@ -0,0 +1,179 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Matches in [SearchResult.Snippet] are wrapped in these.
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+// articles_fts isn't a part of schema.hcl, because atlas doesn't know
+// about virtual tables; it's excluded from migrations, and created here.
+const ftsTable = "articles_fts"
+
+func (s *Sqlite) migrateSearch(ctx context.Context) error {
+	var n int
+	if err := s.db.QueryRowContext(ctx,
+		`select count(*) from sqlite_master where type = 'table' and name = ?`, ftsTable).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `--sql
+	create virtual table articles_fts using fts5(
+		article_id unindexed,
+		title,
+		content,
+		author,
+		tokenize = 'unicode61 remove_diacritics 2'
+	)`); err != nil {
+		return err
+	}
+
+	// index articles saved before the search was added
+	rows, err := tx.QueryContext(ctx, `select id, title, coalesce(content, ''), coalesce(author, '') from articles`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, title, content, author string
+		if serr := rows.Scan(&id, &title, &content, &author); serr != nil {
+			return serr
+		}
+		if err := indexArticle(ctx, tx, id, title, content, author); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func indexArticle(ctx context.Context, tx *sql.Tx, id, title, content, author string) error {
+	_, err := tx.ExecContext(ctx,
+		`insert into articles_fts (article_id, title, content, author) values (?, ?, ?, ?)`,
+		id, title, stripHTML(content), author)
+	return err
+}
+
+// blockTags separate words, unlike inline tags.
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "hr": true, "li": true, "ul": true, "ol": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"blockquote": true, "pre": true, "table": true, "tr": true, "td": true, "th": true,
+	"section": true, "article": true, "figure": true, "figcaption": true, "img": true,
+}
+
+// stripHTML returns text of the html, without tags, scripts and styles.
+func stripHTML(content string) string {
+	var b strings.Builder
+	var skip int
+
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if !errors.Is(z.Err(), io.EOF) {
+				return content
+			}
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch tag := string(name); {
+			case tag == "script" || tag == "style":
+				if tt == html.StartTagToken {
+					skip++
+				} else if tt == html.EndTagToken && skip > 0 {
+					skip--
+				}
+			case blockTags[tag]:
+				b.WriteByte(' ')
+			}
+		case html.TextToken:
+			if skip == 0 {
+				b.Write(z.Text())
+			}
+		}
+	}
+}
+
+type SearchResult struct {
+	// Article is loaded without the content.
+	Article
+
+	// Snippet is a part of the article around matches.
+	Snippet string
+}
+
+// Search returns articles that match the query, best matches first.
+// Words of the query are matched by prefix, in title, content or author.
+func (s *Sqlite) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.QueryContext(ctx, `--sql
+	select a.id, a.feed_id, f.title, a.title,
+		coalesce(a.author, ''), coalesce(a.href, ''), coalesce(a.published_at, 0),
+		s.is_read, s.is_starred,
+		snippet(articles_fts, -1, ?, ?, '…', 16)
+	from articles_fts
+	join articles a on a.id = articles_fts.article_id
+	join feeds f on f.id = a.feed_id
+	join article_statuses s on s.article_id = a.id
+	where articles_fts match ?
+	order by bm25(articles_fts, 0, 10, 1, 5)
+	limit ?`, HighlightStart, HighlightEnd, match, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		if serr := rows.Scan(&r.ID, &r.FeedID, &r.FeedTitle, &r.Title,
+			&r.Author, &r.Href, &r.PublishedAt,
+			&r.IsRead, &r.IsStarred, &r.Snippet); serr != nil {
+			return nil, serr
+		}
+		res = append(res, r)
+	}
+
+	return res, rows.Err()
+}
+
+// ftsQuery turns user input into fts5 query, so the syntax isn't interpreted.
+func ftsQuery(query string) string {
+	words := strings.Fields(query)
+	for i, w := range words {
+		words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"*`
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func searchIDs(results []SearchResult) string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func seedSearch(t *testing.T, db *Sqlite) {
+	t.Helper()
+	ctx := t.Context()
+
+	is.Err(t, db.UpsertSubscription(ctx, "feed/1", "first", "https://a.com/rss", "https://a.com"), nil)
+	is.Err(t, db.UpsertSubscription(ctx, "feed/2", "second", "https://b.com/rss", "https://b.com"), nil)
+	is.Err(t, db.UpsertArticle(ctx, "1", "feed/1", "Generics in Go", "<p>type parameters</p>", "Ian", "", 100), nil)
+	is.Err(t, db.UpsertArticle(ctx, "2", "feed/1", "Release notes", "<p>now with <b>generics</b> &amp; more</p><script>var generics</script>", "", "", 200), nil)
+	is.Err(t, db.UpsertArticle(ctx, "3", "feed/2", "Café", `<a href="https://generics.dev">link</a>`, "Rob", "", 300), nil)
+}
+
+func TestSearch(t *testing.T) {
+	db := newTestStore(t)
+	seedSearch(t, db)
+	ctx := t.Context()
+
+	t.Run("ranks title matches first", func(t *testing.T) {
+		res, err := db.Search(ctx, "generic", 10)
+		is.Err(t, err, nil)
+		is.Equal(t, searchIDs(res), "1,2")
+	})
+
+	t.Run("highlights snippets", func(t *testing.T) {
+		res, err := db.Search(ctx, "more", 10)
+		is.Err(t, err, nil)
+		is.Equal(t, len(res), 1)
+		is.Equal(t, res[0].Snippet, "now with generics & "+HighlightStart+"more"+HighlightEnd)
+	})
+
+	t.Run("author and diacritics", func(t *testing.T) {
+		res, err := db.Search(ctx, "cafe rob", 10)
+		is.Err(t, err, nil)
+		is.Equal(t, searchIDs(res), "3")
+	})
+
+	t.Run("query syntax is escaped", func(t *testing.T) {
+		res, err := db.Search(ctx, `"go" AND (`, 10)
+		is.Err(t, err, nil)
+		is.Equal(t, searchIDs(res), "")
+	})
+
+	t.Run("removed feeds", func(t *testing.T) {
+		is.Err(t, db.RemoveNonExistentFeeds(ctx, []string{"feed/2"}), nil)
+		res, err := db.Search(ctx, "generics cafe", 10)
+		is.Err(t, err, nil)
+		is.Equal(t, searchIDs(res), "")
+
+		var n int
+		is.Err(t, db.db.QueryRowContext(ctx, `select count(*) from articles_fts`).Scan(&n), nil)
+		is.Equal(t, n, 1)
+	})
+}
+
+func TestMigrateKeepsSearchIndex(t *testing.T) {
+	db := newTestStore(t)
+	seedSearch(t, db)
+
+	is.Err(t, db.Migrate(t.Context()), nil)
+	res, err := db.Search(t.Context(), "generics", 10)
+	is.Err(t, err, nil)
+	is.Equal(t, searchIDs(res), "1,2")
+}
+
+func TestStripHTML(t *testing.T) {
+	is.Equal(t, stripHTML(`<p>a <b>bold</b>, <a href="x">link</a>.</p><p>next<br>line</p>`), "a bold, link. next line")
+	is.Equal(t, stripHTML(`<style>p{}</style>text<script>alert(1)</script>`), "text")
+	is.Equal(t, stripHTML(`fish &amp; chips`), "fish & chips")
+}
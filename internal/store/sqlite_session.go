@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Session is the state of the tui, it's restored on the next launch.
+type Session struct {
+	NodeID    string
+	ArticleID string
+
+	// ReaderOpen is set if the article was open in the reader,
+	// Scroll is its scroll position.
+	ReaderOpen bool
+	Scroll     int
+
+	Query       string
+	Author      string
+	OldestFirst bool
+}
+
+func (s *Sqlite) GetSession(ctx context.Context) (Session, error) {
+	var ses Session
+	err := s.db.QueryRowContext(ctx, `--sql
+	select node_id, article_id, reader_open, scroll, query, author, oldest_first
+	from session
+	where id = 1`).
+		Scan(&ses.NodeID, &ses.ArticleID, &ses.ReaderOpen, &ses.Scroll,
+			&ses.Query, &ses.Author, &ses.OldestFirst)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, ErrNotFound
+	}
+	return ses, err
+}
+
+func (s *Sqlite) SaveSession(ctx context.Context, ses Session) error {
+	_, err := s.db.ExecContext(ctx, `--sql
+	insert or replace into session (id, node_id, article_id, reader_open, scroll, query, author, oldest_first)
+	values (1, ?, ?, ?, ?, ?, ?, ?)`,
+		ses.NodeID, ses.ArticleID, ses.ReaderOpen, ses.Scroll,
+		ses.Query, ses.Author, ses.OldestFirst)
+	return err
+}
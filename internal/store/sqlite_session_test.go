@@ -0,0 +1,23 @@
+package store
+
+import (
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestSession(t *testing.T) {
+	db := newTestStore(t)
+	ctx := t.Context()
+
+	_, err := db.GetSession(ctx)
+	is.Err(t, err, ErrNotFound)
+
+	want := Session{NodeID: "feed/1", ArticleID: "5", ReaderOpen: true, Scroll: 12, Author: "rob", OldestFirst: true}
+	is.Err(t, db.SaveSession(ctx, want), nil)
+	is.Err(t, db.SaveSession(ctx, want), nil)
+
+	got, err := db.GetSession(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, got, want)
+}
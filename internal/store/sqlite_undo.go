@@ -0,0 +1,209 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+)
+
+// maxUndoChanges is how many status changes are kept in the undo journal.
+const maxUndoChanges = 100
+
+var statusColumn = map[Action]string{
+	Read:   "is_read",
+	Unread: "is_read",
+	Star:   "is_starred",
+	Unstar: "is_starred",
+}
+
+// nextChangeID returns id for a new change in the journal,
+// and drops the oldest changes, so the journal doesn't grow forever.
+func nextChangeID(ctx context.Context, tx *sql.Tx) (int64, error) {
+	var id int64
+	if err := tx.QueryRowContext(ctx,
+		`select coalesce(max(change_id), 0) + 1 from undo_journal`).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`delete from undo_journal where change_id <= ?`, id-maxUndoChanges); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+type ArticleStatus struct {
+	ID        string
+	IsRead    bool
+	IsStarred bool
+	Labels    []string
+}
+
+// StatusChange is an undone change of article statuses or labels.
+type StatusChange struct {
+	Action Action
+
+	// Articles are statuses of the articles after the undo.
+	Articles []ArticleStatus
+}
+
+type journalEntry struct {
+	articleID string
+	action    Action
+	labelID   string
+	prev      bool
+	pendingID int64
+}
+
+// target is the status (or whether the article has the label) the action sets.
+func (e journalEntry) target() bool {
+	return e.action == Read || e.action == Star || e.action == Label
+}
+
+// UndoStatusChange reverts the last change made with [Sqlite.ChangeArticlesStatus],
+// [Sqlite.MarkAllAsRead], or a label change.
+// If the change wasn't pushed yet, its queued actions are dropped,
+// otherwise the opposite actions are queued.
+// Articles that were changed since, e.g. by sync, are left as they are.
+// Returns [ErrNotFound] if there's nothing to undo.
+func (s *Sqlite) UndoStatusChange(ctx context.Context) (StatusChange, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return StatusChange{}, err
+	}
+	defer tx.Rollback()
+
+	var changeID int64
+	err = tx.QueryRowContext(ctx, `select coalesce(max(change_id), 0) from undo_journal`).Scan(&changeID)
+	if err != nil {
+		return StatusChange{}, err
+	}
+	if changeID == 0 {
+		return StatusChange{}, ErrNotFound
+	}
+
+	entries, err := getJournalEntries(ctx, tx, changeID)
+	if err != nil {
+		return StatusChange{}, err
+	}
+
+	var change StatusChange
+	for _, e := range entries {
+		change.Action = e.action
+
+		current, err := journaledValue(ctx, tx, e)
+		if err != nil {
+			return StatusChange{}, err
+		}
+		if current != e.target() {
+			continue
+		}
+
+		if err := undoEntry(ctx, tx, e); err != nil {
+			return StatusChange{}, err
+		}
+
+		var a ArticleStatus
+		var labels string
+		if err := tx.QueryRowContext(ctx, `--sql
+		select article_id, is_read, is_starred,
+			coalesce((select group_concat(label_id, char(10)) from article_labels where article_id = s.article_id), '')
+		from article_statuses s
+		where article_id = ?`, e.articleID).
+			Scan(&a.ID, &a.IsRead, &a.IsStarred, &labels); err != nil {
+			return StatusChange{}, err
+		}
+		a.Labels = splitLabels(labels)
+		change.Articles = append(change.Articles, a)
+	}
+
+	if _, err := tx.ExecContext(ctx, `delete from undo_journal where change_id = ?`, changeID); err != nil {
+		return StatusChange{}, err
+	}
+
+	return change, tx.Commit()
+}
+
+// journaledValue returns the current status of the article, or whether it has the label.
+func journaledValue(ctx context.Context, tx *sql.Tx, e journalEntry) (bool, error) {
+	var v bool
+	if e.labelID != "" {
+		err := tx.QueryRowContext(ctx,
+			`select exists (select 1 from article_labels where article_id = ? and label_id = ?)`,
+			e.articleID, e.labelID).Scan(&v)
+		return v, err
+	}
+
+	err := tx.QueryRowContext(ctx,
+		`select `+statusColumn[e.action]+` from article_statuses where article_id = ?`, e.articleID).Scan(&v)
+	return v, err
+}
+
+// undoEntry restores the article as it was before the change, and its queued actions.
+func undoEntry(ctx context.Context, tx *sql.Tx, e journalEntry) error {
+	var err error
+	switch {
+	case e.labelID == "":
+		_, err = tx.ExecContext(ctx,
+			`update article_statuses set `+statusColumn[e.action]+` = ? where article_id = ?`,
+			e.prev, e.articleID)
+	case e.prev:
+		_, err = tx.ExecContext(ctx,
+			`insert or ignore into article_labels (article_id, label_id) values (?, ?)`, e.articleID, e.labelID)
+	default:
+		_, err = tx.ExecContext(ctx,
+			`delete from article_labels where article_id = ? and label_id = ?`, e.articleID, e.labelID)
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `delete from pending_actions where id = ?`, e.pendingID)
+	if err != nil {
+		return err
+	}
+
+	// the action was pushed, and it has changed the status on the server
+	pushed, _ := res.RowsAffected()
+	if pushed > 0 || e.prev == e.target() {
+		return nil
+	}
+
+	labelID := sql.NullString{String: e.labelID, Valid: e.labelID != ""}
+	if _, err := tx.ExecContext(ctx, `--sql
+	delete from pending_actions
+	where article_id = ? and action in (?, ?) and label_id is ?`,
+		e.articleID, e.action.String(), e.action.Opposite().String(), labelID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `insert into pending_actions (article_id, action, label_id) values (?, ?, ?)`,
+		e.articleID, e.action.Opposite().String(), labelID)
+	return err
+}
+
+func getJournalEntries(ctx context.Context, tx *sql.Tx, changeID int64) ([]journalEntry, error) {
+	rows, err := tx.QueryContext(ctx, `--sql
+	select article_id, action, coalesce(label_id, ''), prev, pending_action_id
+	from undo_journal
+	where change_id = ?
+	order by id`, changeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []journalEntry
+	for rows.Next() {
+		var e journalEntry
+		var action string
+		if serr := rows.Scan(&e.articleID, &action, &e.labelID, &e.prev, &e.pendingID); serr != nil {
+			return res, serr
+		}
+		if e.action, err = parseAction(action); err != nil {
+			return res, err
+		}
+		res = append(res, e)
+	}
+
+	return res, rows.Err()
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestUndoStatusChange(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	t.Run("nothing to undo", func(t *testing.T) {
+		_, err := db.UndoStatusChange(ctx)
+		is.Err(t, err, ErrNotFound)
+	})
+
+	t.Run("drops queued actions", func(t *testing.T) {
+		is.Err(t, db.ChangeArticlesStatus(ctx, []string{"1", "2"}, Read), nil)
+
+		change, err := db.UndoStatusChange(ctx)
+		is.Err(t, err, nil)
+		is.Equal(t, change.Action, Read)
+		is.Equal(t, len(change.Articles), 2)
+		is.Equal(t, change.Articles[0].IsRead, false)
+
+		unread, err := db.CountArticles(ctx, ArticlesFilter{UnreadOnly: true})
+		is.Err(t, err, nil)
+		is.Equal(t, unread, 5)
+
+		pending, err := db.CountPendingActions(ctx)
+		is.Err(t, err, nil)
+		is.Equal(t, pending, 0)
+	})
+
+	t.Run("queues opposite of pushed actions", func(t *testing.T) {
+		is.Err(t, db.ChangeArticlesStatus(ctx, []string{"3"}, Star), nil)
+		is.Err(t, db.DeletePendingActions(ctx, Star, []string{"3"}), nil)
+
+		_, err := db.UndoStatusChange(ctx)
+		is.Err(t, err, nil)
+
+		queued, err := db.GetPendingActions(ctx, Unstar)
+		is.Err(t, err, nil)
+		is.Equal(t, len(queued), 1)
+
+		starred, err := db.CountArticles(ctx, ArticlesFilter{StarredOnly: true})
+		is.Err(t, err, nil)
+		is.Equal(t, starred, 0)
+	})
+
+	t.Run("keeps status that didn't change", func(t *testing.T) {
+		is.Err(t, db.ChangeArticlesStatus(ctx, []string{"4"}, Read), nil)
+		is.Err(t, db.ChangeArticlesStatus(ctx, []string{"4"}, Read), nil)
+
+		change, err := db.UndoStatusChange(ctx)
+		is.Err(t, err, nil)
+		is.Equal(t, change.Articles[0].IsRead, true)
+
+		change, err = db.UndoStatusChange(ctx)
+		is.Err(t, err, nil)
+		is.Equal(t, change.Articles[0].IsRead, false)
+
+		_, err = db.UndoStatusChange(ctx)
+		is.Err(t, err, ErrNotFound)
+	})
+
+	t.Run("restores replaced queued action", func(t *testing.T) {
+		is.Err(t, db.ChangeArticlesStatus(ctx, []string{"5"}, Read), nil)
+		is.Err(t, db.ChangeArticlesStatus(ctx, []string{"5"}, Unread), nil)
+
+		change, err := db.UndoStatusChange(ctx)
+		is.Err(t, err, nil)
+		is.Equal(t, change.Articles[0].IsRead, true)
+
+		read, err := db.GetPendingActions(ctx, Read)
+		is.Err(t, err, nil)
+		is.Equal(t, len(read), 1)
+
+		unread, err := db.GetPendingActions(ctx, Unread)
+		is.Err(t, err, nil)
+		is.Equal(t, len(unread), 0)
+	})
+}
+
+func TestUndoStatusChange_catchUp(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.ChangeArticlesStatus(ctx, []string{"2"}, Star), nil)
+
+	n, err := db.MarkAllAsRead(ctx, ArticlesFilter{FeedID: "feed/1"}, "feed/1", 5)
+	is.Err(t, err, nil)
+	is.Equal(t, n, 2)
+
+	change, err := db.UndoStatusChange(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, change.Action, Read)
+	is.Equal(t, len(change.Articles), 2)
+
+	unread, _, err := db.GetArticles(ctx, ArticlesFilter{UnreadOnly: true})
+	is.Err(t, err, nil)
+	is.Equal(t, articleIDs(unread), "5,4,3,2,1")
+
+	// the queued mark all as read is pushed first, so the articles are marked back
+	queued, err := db.GetPendingActions(ctx, Unread)
+	is.Err(t, err, nil)
+	is.Equal(t, len(queued), 2)
+
+	// the change made before the catch-up is undone next
+	change, err = db.UndoStatusChange(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, change.Articles[0].ID, "2")
+	is.Equal(t, change.Articles[0].IsStarred, false)
+}
+
+func TestUndoStatusChange_labels(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.AddArticlesLabel(ctx, []string{"1"}, "user/-/label/later"), nil)
+	is.Err(t, db.DeletePendingLabelChange(ctx, LabelChange{
+		Action:     Label,
+		LabelID:    "user/-/label/later",
+		ArticleIDs: []string{"1"},
+	}), nil)
+	is.Err(t, db.AddArticlesLabel(ctx, []string{"1", "2"}, "user/-/label/go"), nil)
+
+	change, err := db.UndoStatusChange(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, change.Action, Label)
+	is.Equal(t, change.Articles[0].Labels, []string{"user/-/label/later"})
+
+	pending, err := db.GetPendingLabelChanges(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, len(pending), 0)
+
+	// the label was pushed already
+	change, err = db.UndoStatusChange(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, len(change.Articles[0].Labels), 0)
+
+	pending, err = db.GetPendingLabelChanges(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, len(pending), 1)
+	is.Equal(t, pending[0].Action, Unlabel)
+}
+
+func TestUndoStatusChange_skipsChangedSince(t *testing.T) {
+	db := newTestStore(t)
+	seedArticles(t, db)
+	ctx := t.Context()
+
+	is.Err(t, db.ChangeArticlesStatus(ctx, []string{"1", "2"}, Star), nil)
+	is.Err(t, db.DeletePendingActions(ctx, Star, []string{"1", "2"}), nil)
+
+	// the article was unstarred on the server since
+	is.Err(t, db.SyncStarredStatus(ctx, []string{"1"}), nil)
+
+	change, err := db.UndoStatusChange(ctx)
+	is.Err(t, err, nil)
+	is.Equal(t, len(change.Articles), 1)
+	is.Equal(t, change.Articles[0].ID, "1")
+
+	queued, err := db.GetPendingActions(ctx, Unstar)
+	is.Err(t, err, nil)
+	is.Equal(t, len(queued), 1)
+}
@@ -0,0 +1,331 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"olexsmir.xyz/smutok/internal/render"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+const articlesPageSize = 100
+
+type articleList struct {
+	nodeID   string
+	filter   store.ArticlesFilter
+	articles []store.Article
+	next     *store.Cursor
+	loading  bool
+
+	// query and author filter articles of every node, until they're cleared
+	query  string
+	author string
+	oldest bool
+
+	selection selection
+
+	cursor int
+	offset int
+}
+
+type articlesLoadedMsg struct {
+	nodeID   string
+	query    string
+	author   string
+	oldest   bool
+	articles []store.Article
+	next     *store.Cursor
+	isNext   bool
+
+	// err is set if the page failed to load
+	err error
+}
+
+func loadArticles(ctx context.Context, db *store.Sqlite, nodeID string, filter store.ArticlesFilter) tea.Cmd {
+	return func() tea.Msg {
+		msg := articlesLoadedMsg{
+			nodeID: nodeID,
+			query:  filter.Query,
+			author: filter.Author,
+			oldest: filter.OldestFirst,
+			isNext: filter.After != nil,
+		}
+
+		articles, next, err := db.GetArticles(ctx, filter)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		for i := range articles {
+			articles[i] = sanitizeArticle(articles[i])
+		}
+		msg.articles, msg.next = articles, next
+		return msg
+	}
+}
+
+type articleLoadedMsg struct{ article store.Article }
+
+func loadArticle(ctx context.Context, db *store.Sqlite, id string) tea.Cmd {
+	return func() tea.Msg {
+		article, err := db.GetArticle(ctx, id)
+		if err != nil {
+			return errMsg{err}
+		}
+		return articleLoadedMsg{sanitizeArticle(article)}
+	}
+}
+
+// sanitizeArticle drops control characters from texts of the article
+// that are shown as they are, the content is sanitized by [render.Render].
+func sanitizeArticle(a store.Article) store.Article {
+	a.Title = render.Sanitize(a.Title)
+	a.Author = render.Sanitize(a.Author)
+	a.FeedTitle = render.Sanitize(a.FeedTitle)
+	return a
+}
+
+// open resets the list to show articles of the node.
+func (l *articleList) open(ctx context.Context, db *store.Sqlite, node sidebarNode) tea.Cmd {
+	// on refresh, e.g. after sync, loaded pages and the selection are kept
+	limit := articlesPageSize
+	if node.id == l.nodeID {
+		limit = max(limit, len(l.articles))
+	} else {
+		l.selection = selection{}
+	}
+
+	l.nodeID = node.id
+	l.filter = node.filter
+	l.filter.Query = l.query
+	l.filter.Author = l.author
+	l.filter.OldestFirst = l.oldest
+	l.filter.Limit = limit
+	l.loading = true
+	return loadArticles(ctx, db, l.nodeID, l.filter)
+}
+
+// setQuery reloads the list filtered by the query.
+func (l *articleList) setQuery(ctx context.Context, db *store.Sqlite, query string) tea.Cmd {
+	return l.setFilter(ctx, db, query, l.author)
+}
+
+// setFilter reloads the list filtered by the query and author.
+func (l *articleList) setFilter(ctx context.Context, db *store.Sqlite, query, author string) tea.Cmd {
+	if query == l.query && author == l.author {
+		return nil
+	}
+
+	l.query, l.author = query, author
+	l.filter.Query, l.filter.Author = query, author
+	l.selection = selection{}
+	l.loading = true
+	return loadArticles(ctx, db, l.nodeID, l.filter)
+}
+
+// setOrder reloads the list in the order.
+func (l *articleList) setOrder(ctx context.Context, db *store.Sqlite, oldest bool) tea.Cmd {
+	if oldest == l.oldest {
+		return nil
+	}
+
+	l.oldest = oldest
+	l.filter.OldestFirst = oldest
+	l.loading = true
+	return loadArticles(ctx, db, l.nodeID, l.filter)
+}
+
+// isStale reports whether the page was requested before the node or filter changed.
+func (l *articleList) isStale(msg articlesLoadedMsg) bool {
+	return msg.nodeID != l.nodeID || msg.query != l.query || msg.author != l.author ||
+		msg.oldest != l.oldest
+}
+
+// loadMore requests the next page, if the cursor is close to the end of the list.
+func (l *articleList) loadMore(ctx context.Context, db *store.Sqlite) tea.Cmd {
+	if l.cursor < len(l.articles)-articlesPageSize/4 {
+		return nil
+	}
+	return l.loadNext(ctx, db)
+}
+
+// loadNext requests the next page, unless it's loading, or it's the last one.
+func (l *articleList) loadNext(ctx context.Context, db *store.Sqlite) tea.Cmd {
+	if l.loading || l.next == nil {
+		return nil
+	}
+
+	filter := l.filter
+	filter.After = l.next
+	l.loading = true
+	return loadArticles(ctx, db, l.nodeID, filter)
+}
+
+func (l *articleList) setPage(msg articlesLoadedMsg) {
+	l.loading = false
+	l.next = msg.next
+	if msg.isNext {
+		l.articles = append(l.articles, msg.articles...)
+		return
+	}
+
+	// keep the cursor on the same article after refresh
+	selected, _ := l.selected()
+	l.articles = msg.articles
+	l.cursor = 0
+	for i, a := range l.articles {
+		if a.ID == selected.ID {
+			l.cursor = i
+			break
+		}
+	}
+}
+
+// selectID moves the cursor to the article, if it's in the list.
+func (l *articleList) selectID(id string) bool {
+	for i, a := range l.articles {
+		if a.ID == id {
+			l.cursor = i
+			return true
+		}
+	}
+	return false
+}
+
+func (l *articleList) find(id string) (store.Article, bool) {
+	for _, a := range l.articles {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return store.Article{}, false
+}
+
+func (l *articleList) selected() (store.Article, bool) {
+	if l.cursor < 0 || l.cursor >= len(l.articles) {
+		return store.Article{}, false
+	}
+	return l.articles[l.cursor], true
+}
+
+// replace updates statuses of the article, if it's in the list.
+func (l *articleList) replace(a store.Article) {
+	for i := range l.articles {
+		if l.articles[i].ID == a.ID {
+			l.articles[i].IsRead = a.IsRead
+			l.articles[i].IsStarred = a.IsStarred
+			l.articles[i].Labels = a.Labels
+			return
+		}
+	}
+}
+
+func (l *articleList) move(delta int) bool {
+	prev := l.cursor
+	l.cursor = clamp(l.cursor+delta, 0, len(l.articles)-1)
+	return prev != l.cursor
+}
+
+// jumpMatch moves to the next or previous article with the query in its title,
+// wrapping around. Articles that match only by the author or feed are skipped,
+// since only titles are shown in the list.
+func (l *articleList) jumpMatch(delta int) bool {
+	if l.query == "" || len(l.articles) == 0 {
+		return false
+	}
+
+	for step := 1; step <= len(l.articles); step++ {
+		i := ((l.cursor+delta*step)%len(l.articles) + len(l.articles)) % len(l.articles)
+		if start, _ := indexFold(l.articles[i].Title, l.query); start != -1 {
+			l.cursor = i
+			return true
+		}
+	}
+	return false
+}
+
+func (l *articleList) filterView() string {
+	var parts []string
+	if l.query != "" {
+		parts = append(parts, "/"+l.query)
+	}
+	if l.author != "" {
+		parts = append(parts, "author:"+l.author)
+	}
+	if l.oldest {
+		parts = append(parts, "oldest first")
+	}
+	if sel := l.selectionView(); sel != "" {
+		parts = append(parts, sel)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (l *articleList) view(width, height int, focused bool) string {
+	var b strings.Builder
+	if header := l.filterView(); header != "" {
+		b.WriteString(mutedStyle.Render(truncate(header, width)))
+		b.WriteByte('\n')
+		height = max(height-1, 0)
+	}
+
+	if len(l.articles) == 0 {
+		b.WriteString(mutedStyle.Render(truncate("no articles", width)))
+		return b.String()
+	}
+
+	l.offset = scrollOffset(l.offset, l.cursor, height, len(l.articles))
+
+	for i := l.offset; i < min(l.offset+height, len(l.articles)); i++ {
+		a := l.articles[i]
+
+		date := formatDate(a.PublishedAt)
+		title := truncate(articleFlags(a)+a.Title+labelsView(a.Labels), max(width-len(date)-1, 0))
+		gap := strings.Repeat(" ", max(width-lipgloss.Width(title)-len(date), 1))
+
+		var line string
+		switch {
+		case i == l.cursor:
+			line = selectionStyle(focused).Width(width).Render(title + gap + date)
+		case l.isMarked(i):
+			line = markedStyle.Width(width).Render(title + gap + date)
+		case a.IsRead:
+			line = mutedStyle.Render(title + gap + date)
+		default:
+			line = title + gap + mutedStyle.Render(date)
+		}
+
+		if i > l.offset {
+			b.WriteByte('\n')
+		}
+		b.WriteString(truncate(line, width))
+	}
+
+	return b.String()
+}
+
+func articleFlags(a store.Article) string {
+	switch {
+	case a.IsStarred:
+		return "★ "
+	case !a.IsRead:
+		return "● "
+	default:
+		return "  "
+	}
+}
+
+func formatDate(unix int64) string {
+	if unix == 0 {
+		return ""
+	}
+
+	t := time.Unix(unix, 0)
+	if t.Year() == time.Now().Year() {
+		return t.Format("Jan 02")
+	}
+	return t.Format("2006-01-02")
+}
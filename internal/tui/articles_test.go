@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/smutok/internal/store"
+	"olexsmir.xyz/x/is"
+)
+
+func TestControlCharacters(t *testing.T) {
+	a := newTestApp(t, nil)
+	ctx := context.Background()
+	is.Err(t, a.db.UpsertSubscription(ctx, "feed/1", "feed\x1b]0;title\x07", "https://a.com/rss", "https://a.com"), nil)
+	is.Err(t, a.db.UpsertArticle(ctx, "4", "feed/1", "x\x1b]52;c;aGk=\x07y", "<p>hi</p>", "\u009b2J", "https://a.com/4", 1770000000), nil)
+
+	a.run(loadSidebar(ctx, a.db))
+	a.press("enter", "g", "enter")
+	is.Equal(t, a.m.reader.article.Title, "x]52;c;aGk=y")
+
+	view := a.m.View()
+	is.Equal(t, strings.Contains(view, "\x1b]"), false)
+	is.Equal(t, strings.Contains(view, "\x07"), false)
+	is.Equal(t, strings.Contains(view, "\u009b"), false)
+}
+
+func TestLoadArticlesFailed(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.press("enter")
+
+	l := &a.m.articles
+	l.next = &store.Cursor{ID: "3"}
+	is.Equal(t, l.loadMore(context.Background(), a.db) != nil, true)
+	is.Equal(t, l.loading, true)
+
+	a.send(articlesLoadedMsg{nodeID: l.nodeID, isNext: true, err: errors.New("disk is on fire")})
+	is.Equal(t, l.loading, false)
+	is.Equal(t, a.m.err.Error(), "disk is on fire")
+	is.Equal(t, a.listed(), "1 2 3 ")
+
+	// the page is requested again
+	is.Equal(t, l.loadMore(context.Background(), a.db) != nil, true)
+}
+
+func TestJumpMatch(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.press("enter", "/")
+	a.typeText("o")
+	a.press("enter")
+	// every article matches by the feed, but only "second" by the title
+	is.Equal(t, a.listed(), "1 2 3 ")
+
+	a.press("n")
+	is.Equal(t, a.m.articles.cursor, 1)
+	a.press("n")
+	is.Equal(t, a.m.articles.cursor, 1)
+	a.press("g", "N")
+	is.Equal(t, a.m.articles.cursor, 1)
+}
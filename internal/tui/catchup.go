@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// catchUpPrompt asks for the "older than N days" cutoff,
+// before marking all articles of the node as read.
+type catchUpPrompt struct {
+	node sidebarNode
+	days string
+}
+
+type markedAllReadMsg struct {
+	title string
+	n     int
+}
+
+func (m *Model) markAllAsRead(node sidebarNode, days int) tea.Cmd {
+	ctx, db := m.ctx, m.store
+	return func() tea.Msg {
+		olderThan := time.Now().AddDate(0, 0, -days).UnixMicro()
+		n, err := db.MarkAllAsRead(ctx, node.filter, node.stream, olderThan)
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to mark %s as read: %w", node.title, err)}
+		}
+		return markedAllReadMsg{title: node.title, n: n}
+	}
+}
+
+func (m *Model) startCatchUp() {
+	if n, ok := m.sidebar.selected(); ok {
+		m.catchUp = &catchUpPrompt{node: n}
+	}
+}
+
+func (m *Model) updateCatchUp(msg tea.KeyMsg) tea.Cmd {
+	switch key := msg.String(); {
+	case len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+		if len(m.catchUp.days) < 4 {
+			m.catchUp.days += key
+		}
+		return nil
+	case key == "backspace":
+		if d := m.catchUp.days; d != "" {
+			m.catchUp.days = d[:len(d)-1]
+		}
+		return nil
+	case key == "enter":
+		days, _ := strconv.Atoi(m.catchUp.days)
+		node := m.catchUp.node
+		m.catchUp = nil
+		return m.queueWrite(m.markAllAsRead(node, days))
+	default:
+		m.catchUp = nil
+		return nil
+	}
+}
+
+func (m *Model) finishCatchUp(msg markedAllReadMsg) tea.Cmd {
+	return tea.Batch(
+		m.showToast(fmt.Sprintf("marked %d articles in %s as read", msg.n, msg.title)),
+		loadSidebar(m.ctx, m.store),
+		loadStatus(m.ctx, m.store, m.worker),
+	)
+}
+
+func (p *catchUpPrompt) view() string {
+	return fmt.Sprintf("mark all in %q as read, older than days: %s▏ enter: confirm, esc: cancel", p.node.title, p.days)
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestCatchUp(t *testing.T) {
+	t.Run("marks older articles", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("A", "1", "enter")
+		is.Equal(t, a.m.toast, `marked 3 articles in Unread as read`)
+		is.Equal(t, a.article("3").IsRead, true)
+	})
+
+	t.Run("undo", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("A", "1", "enter", "u")
+		is.Equal(t, a.m.toast, `undid read of 3 articles`)
+		is.Equal(t, a.article("3").IsRead, false)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("A", "1", "esc")
+		is.Equal(t, a.m.catchUp == nil, true)
+		is.Equal(t, a.article("3").IsRead, false)
+	})
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+// copyToClipboard sets the terminal clipboard with an OSC 52 sequence,
+// so it works over ssh as well. It's written to the output of the program
+// in one write, so it isn't mixed with what the renderer writes.
+func (m *Model) copyToClipboard(text string) error {
+	if m.clipboard == nil {
+		return errors.New("failed to copy to clipboard: the output isn't a terminal")
+	}
+
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+
+	if _, err := io.WriteString(m.clipboard, seq.String()); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return nil
+}
+
+var (
+	markdownEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+	// urls are percent-encoded, so parens and spaces don't end the link early
+	markdownURLEscaper = strings.NewReplacer("(", "%28", ")", "%29", " ", "%20")
+)
+
+// yankText returns text of the article copied by the yank action.
+func yankText(a store.Article, act action) string {
+	switch act {
+	case actYankTitle:
+		return a.Title + " " + a.Href
+	case actYankMarkdown:
+		return fmt.Sprintf("[%s](%s)", markdownEscaper.Replace(a.Title), markdownURLEscaper.Replace(a.Href))
+	default:
+		return a.Href
+	}
+}
+
+func (m *Model) yank(a store.Article, act action) tea.Cmd {
+	if a.Href == "" {
+		return m.showToast("article has no url")
+	}
+
+	what := "url"
+	switch act {
+	case actYankTitle:
+		what = "title and url"
+	case actYankMarkdown:
+		what = "markdown link"
+	}
+	if err := m.copyToClipboard(yankText(a, act)); err != nil {
+		m.handleErr(err)
+		return nil
+	}
+	return m.showToast("copied " + what)
+}
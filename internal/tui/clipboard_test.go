@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"olexsmir.xyz/smutok/internal/store"
+	"olexsmir.xyz/x/is"
+)
+
+func TestYankText(t *testing.T) {
+	a := store.Article{Title: "Go [1.26] released", Href: "https://go.dev/blog"}
+	is.Equal(t, yankText(a, actYankURL), "https://go.dev/blog")
+	is.Equal(t, yankText(a, actYankTitle), "Go [1.26] released https://go.dev/blog")
+	is.Equal(t, yankText(a, actYankMarkdown), `[Go \[1.26\] released](https://go.dev/blog)`)
+
+	a.Href = "https://en.wikipedia.org/wiki/Go_(programming language)"
+	is.Equal(t, yankText(a, actYankMarkdown), `[Go \[1.26\] released](https://en.wikipedia.org/wiki/Go_%28programming%20language%29)`)
+}
+
+func TestYank(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm")
+
+	a := newTestApp(t, nil)
+	var out strings.Builder
+	a.m.clipboard = &out
+	a.press("enter", "y")
+	is.Equal(t, out.String(), osc52.New("https://a.com/1").String())
+	is.Equal(t, a.m.toast, "copied url")
+
+	// not a terminal
+	a.m.clipboard = nil
+	a.press("M")
+	is.Equal(t, a.m.err.Error(), "failed to copy to clipboard: the output isn't a terminal")
+}
@@ -0,0 +1,292 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+var errUsage = errors.New("usage")
+
+// command is run from the command prompt as ":name args".
+type command struct {
+	name    string
+	aliases []string
+	args    string
+	help    string
+
+	run func(m *Model, args string) (tea.Cmd, error)
+
+	// complete returns candidates for the arguments, optional
+	complete func(m *Model, args string) []string
+}
+
+// defaultCommands returns all commands, features add theirs here.
+func defaultCommands() []command {
+	return []command{
+		{name: "sync", help: "sync feeds", run: func(m *Model, _ string) (tea.Cmd, error) {
+			return m.startSync(), nil
+		}},
+		{name: "add", args: "<url> [folder]", help: "subscribe to a feed", run: cmdAdd, complete: completeAdd},
+		{name: "unsubscribe", help: "unsubscribe from the selected feed", run: cmdUnsubscribe},
+		{name: "mv", args: "<folder>", help: "move the selected feed to a folder", run: cmdMove, complete: completeFolders},
+		{name: "rename-folder", args: "<name>", help: "rename the selected folder", run: cmdRenameFolder},
+		{name: "delete-folder", help: "delete the selected folder, keeping its feeds", run: cmdDeleteFolder},
+		{name: "filter", args: "[author:name] [text]", help: "filter articles, clears without args", run: cmdFilter, complete: completeFilter},
+		{name: "sort", args: "newest|oldest", help: "change order of articles", run: cmdSort, complete: completeSort},
+		{name: "mark-all-read", args: "[days]", help: "mark all in the selected node as read", run: cmdMarkAllRead},
+		{name: "read", help: "mark selected articles as read", run: statusCommand(store.Read)},
+		{name: "unread", help: "mark selected articles as unread", run: statusCommand(store.Unread)},
+		{name: "star", help: "star selected articles", run: statusCommand(store.Star)},
+		{name: "unstar", help: "unstar selected articles", run: statusCommand(store.Unstar)},
+		{name: "label", args: "<name>", help: "add a label to selected articles", run: labelCommand(store.Label), complete: completeFolders},
+		{name: "unlabel", args: "<name>", help: "remove a label from selected articles", run: labelCommand(store.Unlabel), complete: completeLabels},
+		{name: "undo", help: "undo the last status change", run: func(m *Model, _ string) (tea.Cmd, error) {
+			return m.undoStatus(), nil
+		}},
+		{name: "open", help: "open the article in browser", run: cmdOpen},
+		{name: "search", args: "<text>", help: "search all articles", run: cmdSearch},
+		{name: "set", args: "<option>=<value>", help: "change an option until restart", run: cmdSet, complete: completeSet},
+		{name: "help", help: "show help", run: func(m *Model, _ string) (tea.Cmd, error) {
+			m.overlay = helpOverlay
+			return nil, nil
+		}},
+		{name: "quit", aliases: []string{"q"}, help: "quit", run: func(m *Model, _ string) (tea.Cmd, error) {
+			return m.quit(), nil
+		}},
+	}
+}
+
+func findCommand(commands []command, name string) (command, bool) {
+	i := slices.IndexFunc(commands, func(c command) bool {
+		return c.name == name || slices.Contains(c.aliases, name)
+	})
+	if i == -1 {
+		return command{}, false
+	}
+	return commands[i], true
+}
+
+// runCommand parses the line and runs the command.
+func (m *Model) runCommand(line string) tea.Cmd {
+	name, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+	if name == "" {
+		return nil
+	}
+
+	c, ok := findCommand(m.commands, name)
+	if !ok {
+		m.handleErr(fmt.Errorf("unknown command: %s", name))
+		return nil
+	}
+
+	cmd, err := c.run(m, strings.TrimSpace(args))
+	if errors.Is(err, errUsage) {
+		err = fmt.Errorf("usage: %s %s", c.name, c.args)
+	}
+	if err != nil {
+		m.handleErr(err)
+	}
+	return cmd
+}
+
+// completeCommand returns completions of the whole line.
+func (m *Model) completeCommand(line string) []string {
+	name, args, hasArgs := strings.Cut(line, " ")
+	if !hasArgs {
+		var res []string
+		for _, c := range m.commands {
+			res = append(res, c.name)
+		}
+		return res
+	}
+
+	c, ok := findCommand(m.commands, name)
+	if !ok || c.complete == nil {
+		return nil
+	}
+
+	candidates := c.complete(m, args)
+	res := make([]string, len(candidates))
+	for i, s := range candidates {
+		res[i] = name + " " + s
+	}
+	return res
+}
+
+func (m *Model) folderNames() []string {
+	res := make([]string, len(m.sidebar.folders))
+	for i, f := range m.sidebar.folders {
+		res[i] = f.Name
+	}
+	return res
+}
+
+func completeFolders(m *Model, _ string) []string {
+	return m.folderNames()
+}
+
+func completeAdd(m *Model, args string) []string {
+	url, _, ok := strings.Cut(args, " ")
+	if !ok {
+		return nil
+	}
+
+	names := m.folderNames()
+	for i, name := range names {
+		names[i] = url + " " + name
+	}
+	return names
+}
+
+func cmdAdd(m *Model, args string) (tea.Cmd, error) {
+	url, folder, _ := strings.Cut(args, " ")
+	if url == "" {
+		return nil, errUsage
+	}
+	return m.subscribe(url, strings.TrimSpace(folder)), nil
+}
+
+func cmdUnsubscribe(m *Model, _ string) (tea.Cmd, error) {
+	id, title, ok := m.selectedFeed()
+	if !ok {
+		return nil, errors.New("no feed selected")
+	}
+	return m.unsubscribe(id, title), nil
+}
+
+func cmdMove(m *Model, args string) (tea.Cmd, error) {
+	if args == "" {
+		return nil, errUsage
+	}
+
+	id, title, ok := m.selectedFeed()
+	if !ok {
+		return nil, errors.New("no feed selected")
+	}
+	return m.moveFeed(id, title, args), nil
+}
+
+func cmdRenameFolder(m *Model, args string) (tea.Cmd, error) {
+	if args == "" {
+		return nil, errUsage
+	}
+
+	name, ok := m.selectedFolder()
+	if !ok {
+		return nil, errors.New("no folder selected")
+	}
+	return m.renameFolder(name, args), nil
+}
+
+func cmdDeleteFolder(m *Model, _ string) (tea.Cmd, error) {
+	name, ok := m.selectedFolder()
+	if !ok {
+		return nil, errors.New("no folder selected")
+	}
+	m.prompt = newPrompt(deleteFolderPrompt, fmt.Sprintf("delete folder %s? (y/n) ", name), "")
+	m.prompt.target = name
+	return nil, nil
+}
+
+const authorFilterPrefix = "author:"
+
+// parseFilter splits the filter into the author and the rest of the text.
+func parseFilter(args string) (query, author string) {
+	var words []string
+	for _, w := range strings.Fields(args) {
+		if a, ok := strings.CutPrefix(w, authorFilterPrefix); ok {
+			author = a
+			continue
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, " "), author
+}
+
+func completeFilter(m *Model, args string) []string {
+	if !strings.HasPrefix(authorFilterPrefix, args) && !strings.HasPrefix(args, authorFilterPrefix) {
+		return nil
+	}
+
+	var res []string
+	for _, a := range m.articles.articles {
+		if a.Author == "" || strings.Contains(a.Author, " ") {
+			continue
+		}
+		if s := authorFilterPrefix + a.Author; !slices.Contains(res, s) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func cmdFilter(m *Model, args string) (tea.Cmd, error) {
+	query, author := parseFilter(args)
+	return m.articles.setFilter(m.ctx, m.store, query, author), nil
+}
+
+func completeSort(*Model, string) []string {
+	return []string{"newest", "oldest"}
+}
+
+func cmdSort(m *Model, args string) (tea.Cmd, error) {
+	switch args {
+	case "newest":
+		return m.articles.setOrder(m.ctx, m.store, false), nil
+	case "oldest":
+		return m.articles.setOrder(m.ctx, m.store, true), nil
+	default:
+		return nil, errUsage
+	}
+}
+
+func cmdMarkAllRead(m *Model, args string) (tea.Cmd, error) {
+	var days int
+	if args != "" {
+		var err error
+		if days, err = strconv.Atoi(args); err != nil || days < 0 {
+			return nil, errUsage
+		}
+	}
+
+	n, ok := m.sidebar.selected()
+	if !ok {
+		return nil, errors.New("nothing selected")
+	}
+	return m.markAllAsRead(n, days), nil
+}
+
+func statusCommand(action store.Action) func(*Model, string) (tea.Cmd, error) {
+	return func(m *Model, _ string) (tea.Cmd, error) {
+		if len(m.targetArticles()) == 0 {
+			return nil, errors.New("no articles selected")
+		}
+		return m.bulkStatus(func(articles ...store.Article) tea.Cmd {
+			return m.setStatus(action, articles...)
+		}), nil
+	}
+}
+
+func cmdOpen(m *Model, _ string) (tea.Cmd, error) {
+	a, ok := m.articles.selected()
+	if m.focus == readerPane && m.reader.article != nil {
+		a, ok = *m.reader.article, true
+	}
+	if !ok || a.Href == "" {
+		return nil, errors.New("no article to open")
+	}
+	return m.openURLs(a.Href), nil
+}
+
+func cmdSearch(m *Model, args string) (tea.Cmd, error) {
+	if args == "" {
+		return nil, errUsage
+	}
+	return runSearch(m.ctx, m.store, args), nil
+}
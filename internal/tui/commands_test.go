@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/smutok/internal/config"
+	"olexsmir.xyz/smutok/internal/store"
+	"olexsmir.xyz/x/is"
+)
+
+func TestCompleteCommand(t *testing.T) {
+	m := &Model{commands: defaultCommands(), cfg: &config.Config{}}
+	m.sidebar.folders = []store.Folder{{Name: "News"}, {Name: "Tech"}}
+
+	t.Run("names", func(t *testing.T) {
+		is.Equal(t, len(m.completeCommand("")), len(m.commands))
+	})
+
+	t.Run("folders", func(t *testing.T) {
+		is.Equal(t, strings.Join(m.completeCommand("mv T"), ","), "mv News,mv Tech")
+		is.Equal(t, strings.Join(m.completeCommand("add https://a.com/rss "), ","),
+			"add https://a.com/rss News,add https://a.com/rss Tech")
+		is.Equal(t, len(m.completeCommand("add https://a.com/rss")), 0)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		m.cfg.Read.OnOpen = true
+		is.Equal(t, m.completeCommand("set ")[0], "set read.on_open=true")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		is.Equal(t, len(m.completeCommand("fly ")), 0)
+	})
+}
+
+func TestRunCommand(t *testing.T) {
+	m := &Model{commands: defaultCommands(), cfg: &config.Config{}}
+
+	m.runCommand("set read.after_seconds=5")
+	is.Equal(t, m.cfg.Read.AfterSeconds, 5)
+	is.Equal(t, m.err, nil)
+
+	m.runCommand("set read.after_seconds=soon")
+	is.Equal(t, m.cfg.Read.AfterSeconds, 5)
+	is.Equal(t, m.err.Error(), "read.after_seconds: expected a non-negative number")
+
+	m.runCommand("set open.command=firefox --new-tab")
+	is.Equal(t, m.toast, "open.command=firefox --new-tab")
+
+	m.runCommand("mv")
+	is.Equal(t, m.err.Error(), "usage: mv <folder>")
+
+	m.runCommand("delete-folder")
+	is.Equal(t, m.err.Error(), "no folder selected")
+
+	m.runCommand("fly away")
+	is.Equal(t, m.err.Error(), "unknown command: fly")
+
+	c, ok := findCommand(m.commands, "q")
+	is.Equal(t, ok, true)
+	is.Equal(t, c.name, "quit")
+}
+
+func TestParseFilter(t *testing.T) {
+	query, author := parseFilter("go author:rob  generics")
+	is.Equal(t, query, "go generics")
+	is.Equal(t, author, "rob")
+
+	query, author = parseFilter("")
+	is.Equal(t, query, "")
+	is.Equal(t, author, "")
+}
+
+func TestQuitCommand(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.press(":")
+	a.typeText("q")
+	a.press("enter")
+	is.Equal(t, a.quit, true)
+}
+
+func TestDeleteFolderCommand(t *testing.T) {
+	a := newTestApp(t, nil)
+	for n, _ := a.m.sidebar.selected(); n.kind != folderNode; n, _ = a.m.sidebar.selected() {
+		a.press("j")
+	}
+
+	deleteFolder := func(answer string) {
+		a.press(":")
+		a.typeText("delete-folder")
+		a.press("enter")
+		is.Equal(t, a.m.prompt.input.Prompt, "delete folder Tech? (y/n) ")
+		a.typeText(answer)
+		a.press("enter")
+	}
+
+	deleteFolder("n")
+	is.Equal(t, len(a.m.sidebar.folders), 1)
+
+	deleteFolder("y")
+	is.Equal(t, len(a.m.sidebar.folders), 0)
+	is.Equal(t, a.m.toast, "deleted folder Tech")
+}
@@ -1,6 +1,16 @@
 package tui
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/render"
+)
+
+const maxErrHistory = 50
 
 type errMsg struct{ err error }
 
@@ -8,8 +18,53 @@ func (e errMsg) Error() string {
 	return e.err.Error()
 }
 
-func sendErr(err error) tea.Cmd {
+type errEntry struct {
+	at  time.Time
+	err error
+}
+
+// waitWorkerErr waits for the next error of the worker.
+func waitWorkerErr(ctx context.Context, worker Worker) tea.Cmd {
 	return func() tea.Msg {
-		return errMsg{err}
+		select {
+		case err := <-worker.Errors():
+			return workerErrMsg{err}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+type workerErrMsg struct{ err error }
+
+func (m *Model) handleErr(err error) {
+	m.err = err
+	m.showErr = true
+
+	m.errHistory = append(m.errHistory, errEntry{at: time.Now(), err: err})
+	if len(m.errHistory) > maxErrHistory {
+		m.errHistory = m.errHistory[len(m.errHistory)-maxErrHistory:]
+	}
+}
+
+func (m *Model) errBannerView(width int) string {
+	hint := fmt.Sprintf("  %s: dismiss, %s: history", m.keys.hint(actDismiss), m.keys.hint(actErrHistory))
+	return errStyle.Render(truncate("error: "+render.Sanitize(m.err.Error()), max(width-len(hint), 0))) +
+		mutedStyle.Render(hint)
+}
+
+func (m *Model) errHistoryView(width, height int) string {
+	if len(m.errHistory) == 0 {
+		return mutedStyle.Render("no errors")
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Errors"))
+	for i := len(m.errHistory) - 1; i >= 0 && len(m.errHistory)-i < height; i-- {
+		e := m.errHistory[i]
+		b.WriteByte('\n')
+		b.WriteString(mutedStyle.Render(e.at.Format("15:04:05") + " "))
+		b.WriteString(truncate(render.Sanitize(e.err.Error()), max(width-9, 0)))
 	}
+	return b.String()
 }
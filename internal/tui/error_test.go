@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestErrorBanner(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.send(errMsg{errors.New("connection refused")})
+	is.Equal(t, a.m.showErr, true)
+	is.Equal(t, strings.Contains(a.m.View(), "connection refused"), true)
+
+	a.press("esc")
+	is.Equal(t, a.m.showErr, false)
+
+	a.press("E")
+	is.Equal(t, a.m.overlay, errHistoryOverlay)
+	is.Equal(t, strings.Contains(a.m.View(), "connection refused"), true)
+	a.press("esc")
+	is.Equal(t, a.m.overlay, noOverlay)
+}
+
+func TestWorkerErrors(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.send(workerErrMsg{errors.New("push failed")})
+	is.Equal(t, a.m.showErr, true)
+	is.Equal(t, a.m.err.Error(), "push failed")
+	is.Equal(t, len(a.m.errHistory), 1)
+}
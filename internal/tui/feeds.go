@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"olexsmir.xyz/smutok/internal/freshrss"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+// Editor changes subscriptions on the server and in the store.
+type Editor interface {
+	Subscribe(ctx context.Context, url, folder string) (store.Feed, error)
+	Unsubscribe(ctx context.Context, feedID string) error
+	Move(ctx context.Context, feedID, folder string) error
+	Rename(ctx context.Context, feedID, title string) error
+	RenameFolder(ctx context.Context, name, newName string) error
+	DeleteFolder(ctx context.Context, name string) error
+}
+
+// feedsChangedMsg is sent after subscriptions were changed.
+type feedsChangedMsg struct {
+	text string
+
+	// nodeID is selected in the sidebar after it's reloaded, optional
+	nodeID string
+}
+
+func (m *Model) editFeeds(fn func() (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		text, err := fn()
+		if err != nil {
+			return errMsg{err}
+		}
+		return feedsChangedMsg{text: text}
+	}
+}
+
+func (m *Model) subscribe(url, folder string) tea.Cmd {
+	ctx, editor := m.ctx, m.editor
+	return m.editFeeds(func() (string, error) {
+		feed, err := editor.Subscribe(ctx, url, folder)
+		if err != nil {
+			return "", fmt.Errorf("failed to subscribe to %s: %w", url, err)
+		}
+		return "subscribed to " + feed.Title, nil
+	})
+}
+
+func (m *Model) unsubscribe(feedID, title string) tea.Cmd {
+	ctx, editor := m.ctx, m.editor
+	return m.editFeeds(func() (string, error) {
+		if err := editor.Unsubscribe(ctx, feedID); err != nil {
+			return "", fmt.Errorf("failed to unsubscribe from %s: %w", title, err)
+		}
+		return "unsubscribed from " + title, nil
+	})
+}
+
+func (m *Model) moveFeed(feedID, title, folder string) tea.Cmd {
+	ctx, editor := m.ctx, m.editor
+	return m.editFeeds(func() (string, error) {
+		if err := editor.Move(ctx, feedID, folder); err != nil {
+			return "", fmt.Errorf("failed to move %s: %w", title, err)
+		}
+		return fmt.Sprintf("moved %s to %s", title, folder), nil
+	})
+}
+
+func (m *Model) renameFeed(feedID, title, newTitle string) tea.Cmd {
+	ctx, editor := m.ctx, m.editor
+	return m.editFeeds(func() (string, error) {
+		if err := editor.Rename(ctx, feedID, newTitle); err != nil {
+			return "", fmt.Errorf("failed to rename %s: %w", title, err)
+		}
+		return fmt.Sprintf("renamed %s to %s", title, newTitle), nil
+	})
+}
+
+func (m *Model) renameFolder(name, newName string) tea.Cmd {
+	ctx, editor := m.ctx, m.editor
+	return func() tea.Msg {
+		if err := editor.RenameFolder(ctx, name, newName); err != nil {
+			return errMsg{fmt.Errorf("failed to rename folder %s: %w", name, err)}
+		}
+		return feedsChangedMsg{
+			text:   fmt.Sprintf("renamed folder %s to %s", name, newName),
+			nodeID: freshrss.LabelID(newName),
+		}
+	}
+}
+
+func (m *Model) deleteFolder(name string) tea.Cmd {
+	ctx, editor := m.ctx, m.editor
+	return m.editFeeds(func() (string, error) {
+		if err := editor.DeleteFolder(ctx, name); err != nil {
+			return "", fmt.Errorf("failed to delete folder %s: %w", name, err)
+		}
+		return "deleted folder " + name, nil
+	})
+}
+
+func (m *Model) finishFeedsChange(msg feedsChangedMsg) tea.Cmd {
+	m.sidebar.pendingID = msg.nodeID
+	return tea.Batch(
+		m.showToast(msg.text),
+		loadSidebar(m.ctx, m.store),
+		loadStatus(m.ctx, m.store, m.worker),
+	)
+}
+
+// selectedFeed returns the selected feed in the sidebar,
+// or the feed of the selected article in other panes.
+func (m *Model) selectedFeed() (id, title string, ok bool) {
+	switch m.focus {
+	case sidebarPane:
+		if n, ok := m.sidebar.selected(); ok && n.kind == feedNode {
+			return n.id, n.title, true
+		}
+	case articlesPane:
+		if a, ok := m.articles.selected(); ok {
+			return a.FeedID, a.FeedTitle, true
+		}
+	case readerPane:
+		if a := m.reader.article; a != nil {
+			return a.FeedID, a.FeedTitle, true
+		}
+	}
+	return "", "", false
+}
+
+// selectedFolder returns name of the folder selected in the sidebar.
+func (m *Model) selectedFolder() (string, bool) {
+	if n, ok := m.sidebar.selected(); ok && n.kind == folderNode {
+		return n.title, true
+	}
+	return "", false
+}
+
+const feedManagerHint = "a: subscribe, r: rename, m: move, d: unsubscribe, esc: close"
+
+// feedManager lists feeds with their folders, to manage subscriptions.
+type feedManager struct {
+	cursor int
+	offset int
+}
+
+func (m *Model) openFeedManager() {
+	m.feeds = feedManager{}
+	if id, _, ok := m.selectedFeed(); ok {
+		for i, f := range m.sidebar.feeds {
+			if f.ID == id {
+				m.feeds.cursor = i
+			}
+		}
+	}
+	m.overlay = feedsOverlay
+}
+
+func (m *Model) managedFeed() (store.Feed, bool) {
+	c := m.feeds.cursor
+	if c < 0 || c >= len(m.sidebar.feeds) {
+		return store.Feed{}, false
+	}
+	return m.sidebar.feeds[c], true
+}
+
+func (m *Model) updateFeedManager(msg tea.KeyMsg) tea.Cmd {
+	f := &m.feeds
+	last := max(len(m.sidebar.feeds)-1, 0)
+
+	key := msg.String()
+	switch {
+	case m.keys.is(key, actDown):
+		f.cursor = clamp(f.cursor+1, 0, last)
+	case m.keys.is(key, actUp):
+		f.cursor = clamp(f.cursor-1, 0, last)
+	case m.keys.is(key, actTop):
+		f.cursor = 0
+	case m.keys.is(key, actBottom):
+		f.cursor = last
+	case key == "a":
+		m.openPrompt(subscribePrompt)
+	case key == "r":
+		if feed, ok := m.managedFeed(); ok {
+			m.prompt = newPrompt(renamePrompt, "rename to: ", feed.Title)
+			m.prompt.target = feed.ID
+		}
+	case key == "m":
+		if feed, ok := m.managedFeed(); ok {
+			m.prompt = newPrompt(movePrompt, "move to folder: ", "")
+			m.prompt.target = feed.ID
+			m.prompt.input.ShowSuggestions = true
+			m.prompt.input.SetSuggestions(m.folderNames())
+		}
+	case key == "d":
+		if feed, ok := m.managedFeed(); ok {
+			m.prompt = newPrompt(unsubscribePrompt, fmt.Sprintf("unsubscribe from %s? (y/n) ", feed.Title), "")
+			m.prompt.target = feed.ID
+		}
+	case key == "esc", m.keys.is(key, actQuit), m.keys.is(key, actManageFeeds):
+		m.overlay = noOverlay
+	}
+	return nil
+}
+
+func (m *Model) feedTitle(feedID string) string {
+	for _, f := range m.sidebar.feeds {
+		if f.ID == feedID {
+			return f.Title
+		}
+	}
+	return feedID
+}
+
+// feedFolders returns names of folders of the feed.
+func (m *Model) feedFolders(feedID string) string {
+	var names []string
+	for _, folder := range m.sidebar.folders {
+		for _, id := range folder.FeedIDs {
+			if id == feedID {
+				names = append(names, folder.Name)
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func (m *Model) feedManagerView(width, height int) string {
+	feeds := m.sidebar.feeds
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Feeds (%d)", len(feeds))))
+	if len(feeds) == 0 {
+		b.WriteString("\n" + mutedStyle.Render("no feeds"))
+		return b.String()
+	}
+
+	rows := max(height-1, 1)
+	f := &m.feeds
+	f.cursor = clamp(f.cursor, 0, len(feeds)-1)
+	f.offset = scrollOffset(f.offset, f.cursor, rows, len(feeds))
+
+	titleWidth := clamp(width/3, 10, 40)
+	folderWidth := clamp(width/5, 8, 24)
+	for i := f.offset; i < min(f.offset+rows, len(feeds)); i++ {
+		feed := feeds[i]
+		title := truncate(feed.Title, titleWidth)
+		folder := truncate(m.feedFolders(feed.ID), folderWidth)
+		line := fmt.Sprintf("%s%s  %s%s  ",
+			title, strings.Repeat(" ", titleWidth-lipgloss.Width(title)),
+			folder, strings.Repeat(" ", folderWidth-lipgloss.Width(folder)))
+		line = truncate(line+mutedStyle.Render(feed.URL), width)
+
+		b.WriteByte('\n')
+		if i == f.cursor {
+			b.WriteString(selectedStyle.Width(width).Render(ansi.Strip(line)))
+		} else {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestFeedManager(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.press("F")
+	is.Equal(t, a.m.overlay, feedsOverlay)
+
+	a.press("r")
+	for range len("Go Blog") {
+		a.press("backspace")
+	}
+	a.typeText("The Go Blog")
+	a.press("enter")
+	is.Equal(t, a.m.toast, "renamed Go Blog to The Go Blog")
+	is.Equal(t, a.article("1").FeedTitle, "The Go Blog")
+
+	// the feeds are sorted by title
+	a.press("d")
+	a.typeText("y")
+	a.press("enter")
+	is.Equal(t, a.m.toast, "unsubscribed from Lonely")
+	is.Equal(t, len(a.m.sidebar.feeds), 1)
+	is.Equal(t, a.listed(), "1 2 ")
+}
@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type action string
+
+const (
+	actQuit         action = "quit"
+	actHelp         action = "help"
+	actNextPane     action = "next_pane"
+	actPrevPane     action = "prev_pane"
+	actSync         action = "sync"
+	actErrHistory   action = "error_history"
+	actDismiss      action = "dismiss"
+	actDown         action = "down"
+	actUp           action = "up"
+	actTop          action = "top"
+	actBottom       action = "bottom"
+	actPageDown     action = "page_down"
+	actPageUp       action = "page_up"
+	actOpen         action = "open"
+	actBack         action = "back"
+	actToggleFolder action = "toggle_folder"
+	actToggleRead   action = "toggle_read"
+	actToggleStar   action = "toggle_star"
+	actGrowPane     action = "grow_pane"
+	actShrinkPane   action = "shrink_pane"
+	actResetPanes   action = "reset_panes"
+	actLinks        action = "links"
+	actOpenArticle  action = "open_article"
+	actYankURL      action = "yank_url"
+	actYankTitle    action = "yank_title"
+	actYankMarkdown action = "yank_markdown"
+	actMarkAllRead  action = "mark_all_read"
+	actSearch       action = "search"
+	actNextMatch    action = "next_match"
+	actPrevMatch    action = "prev_match"
+	actFullSearch   action = "full_search"
+	actCommand      action = "command"
+	actMark         action = "mark"
+	actVisual       action = "visual"
+	actUndo         action = "undo"
+	actManageFeeds  action = "manage_feeds"
+	actLabel        action = "label"
+)
+
+type binding struct {
+	action action
+	keys   []string
+	help   string
+
+	// panes the binding works in, all panes if empty
+	panes []pane
+}
+
+func (b binding) in(p pane) bool {
+	return len(b.panes) == 0 || slices.Contains(b.panes, p)
+}
+
+func defaultBindings() []binding {
+	articlePanes := []pane{articlesPane, readerPane}
+	listPanes := []pane{sidebarPane, articlesPane}
+	return []binding{
+		{action: actDismiss, keys: []string{"esc"}, help: "dismiss error or selection"},
+		{action: actQuit, keys: []string{"q", "ctrl+c"}, help: "quit"},
+		{action: actHelp, keys: []string{"?"}, help: "show help"},
+		{action: actNextPane, keys: []string{"tab"}, help: "focus next pane"},
+		{action: actPrevPane, keys: []string{"shift+tab"}, help: "focus previous pane"},
+		{action: actSync, keys: []string{"R"}, help: "sync feeds"},
+		{action: actErrHistory, keys: []string{"E"}, help: "show errors"},
+		{action: actFullSearch, keys: []string{"S"}, help: "search all articles"},
+		{action: actCommand, keys: []string{":"}, help: "run a command"},
+		{action: actUndo, keys: []string{"u"}, help: "undo last status or label change"},
+		{action: actManageFeeds, keys: []string{"F"}, help: "manage feeds"},
+		{action: actGrowPane, keys: []string{">"}, help: "widen pane", panes: listPanes},
+		{action: actShrinkPane, keys: []string{"<"}, help: "narrow pane", panes: listPanes},
+		{action: actResetPanes, keys: []string{"="}, help: "reset pane sizes"},
+
+		{action: actDown, keys: []string{"j", "down"}, help: "move down"},
+		{action: actUp, keys: []string{"k", "up"}, help: "move up"},
+		{action: actTop, keys: []string{"g", "home"}, help: "go to top"},
+		{action: actBottom, keys: []string{"G", "end"}, help: "go to bottom"},
+		{action: actPageDown, keys: []string{"ctrl+d", "pgdown"}, help: "page down"},
+		{action: actPageUp, keys: []string{"ctrl+u", "pgup"}, help: "page up"},
+
+		{action: actOpen, keys: []string{"enter", "l", "right"}, help: "open", panes: []pane{sidebarPane, articlesPane}},
+		{action: actBack, keys: []string{"h", "left", "esc"}, help: "go back", panes: articlePanes},
+		{action: actToggleFolder, keys: []string{" "}, help: "collapse/expand folder", panes: []pane{sidebarPane}},
+		{action: actMarkAllRead, keys: []string{"A"}, help: "mark all as read", panes: listPanes},
+		{action: actMark, keys: []string{" "}, help: "select article", panes: []pane{articlesPane}},
+		{action: actVisual, keys: []string{"v"}, help: "select range", panes: []pane{articlesPane}},
+		{action: actToggleRead, keys: []string{"r"}, help: "toggle read", panes: articlePanes},
+		{action: actToggleStar, keys: []string{"s"}, help: "toggle star", panes: articlePanes},
+		{action: actLabel, keys: []string{"L"}, help: "label article", panes: articlePanes},
+		{action: actSearch, keys: []string{"/"}, help: "search", panes: articlePanes},
+		{action: actNextMatch, keys: []string{"n"}, help: "next match", panes: articlePanes},
+		{action: actPrevMatch, keys: []string{"N"}, help: "previous match", panes: articlePanes},
+		{action: actOpenArticle, keys: []string{"O"}, help: "open article in browser", panes: articlePanes},
+		{action: actLinks, keys: []string{"o"}, help: "pick links to open", panes: []pane{readerPane}},
+		{action: actYankURL, keys: []string{"y"}, help: "copy url", panes: articlePanes},
+		{action: actYankTitle, keys: []string{"Y"}, help: "copy title and url", panes: articlePanes},
+		{action: actYankMarkdown, keys: []string{"M"}, help: "copy markdown link", panes: articlePanes},
+	}
+}
+
+type keyMap struct {
+	bindings []binding
+}
+
+// newKeyMap returns the default key map, with keys of actions replaced by overrides.
+func newKeyMap(overrides map[string][]string) (keyMap, error) {
+	bindings := defaultBindings()
+	for name, keys := range overrides {
+		i := slices.IndexFunc(bindings, func(b binding) bool { return string(b.action) == name })
+		if i == -1 {
+			return keyMap{}, fmt.Errorf("unknown key action: %q", name)
+		}
+
+		bindings[i].keys = make([]string, len(keys))
+		for j, key := range keys {
+			if key == "space" {
+				key = " "
+			}
+			bindings[i].keys[j] = key
+		}
+	}
+	return keyMap{bindings: bindings}, nil
+}
+
+// actions returns actions bound to the key in the pane, in order of priority.
+func (k keyMap) actions(key string, p pane) []action {
+	var res []action
+	for _, b := range k.bindings {
+		if b.in(p) && slices.Contains(b.keys, key) {
+			res = append(res, b.action)
+		}
+	}
+	return res
+}
+
+func (k keyMap) is(key string, a action) bool {
+	for _, b := range k.bindings {
+		if b.action == a && slices.Contains(b.keys, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// hint returns the first key of the action, for showing it in the ui.
+func (k keyMap) hint(a action) string {
+	for _, b := range k.bindings {
+		if b.action == a && len(b.keys) > 0 {
+			return keyName(b.keys[0])
+		}
+	}
+	return ""
+}
+
+// help returns bindings that work in the pane.
+func (k keyMap) help(p pane) []binding {
+	var res []binding
+	for _, b := range k.bindings {
+		if b.in(p) && len(b.keys) > 0 {
+			res = append(res, b)
+		}
+	}
+	return res
+}
+
+func keyName(key string) string {
+	if key == " " {
+		return "space"
+	}
+	return key
+}
+
+func (m *Model) helpView(width, height int) string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Keys: " + m.focus.String()))
+
+	lines := 1
+	bindings := m.keys.help(m.focus)
+	for _, bind := range bindings {
+		if lines >= height {
+			return b.String()
+		}
+		lines++
+
+		keys := make([]string, len(bind.keys))
+		for j, key := range bind.keys {
+			keys[j] = keyName(key)
+		}
+
+		b.WriteByte('\n')
+		b.WriteString(truncate(fmt.Sprintf("%-20s %s", strings.Join(keys, ", "), mutedStyle.Render(bind.help)), width))
+	}
+
+	if lines+2 > height {
+		return b.String()
+	}
+	b.WriteString("\n\n" + titleStyle.Render("Commands"))
+	lines += 2
+	for _, c := range m.commands {
+		if lines >= height {
+			break
+		}
+		lines++
+
+		b.WriteByte('\n')
+		b.WriteString(truncate(fmt.Sprintf("%-20s %s", strings.TrimSpace(":"+c.name+" "+c.args), mutedStyle.Render(c.help)), width))
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/smutok/internal/config"
+	"olexsmir.xyz/x/is"
+)
+
+func TestNewKeyMap(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		k, err := newKeyMap(nil)
+		is.Err(t, err, nil)
+		is.Equal(t, k.is("j", actDown), true)
+		is.Equal(t, k.is(" ", actToggleFolder), true)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		k, err := newKeyMap(map[string][]string{
+			"toggle_read":   {"N"},
+			"toggle_folder": {"space", "o"},
+		})
+		is.Err(t, err, nil)
+		is.Equal(t, k.is("N", actToggleRead), true)
+		is.Equal(t, k.is("r", actToggleRead), false)
+		is.Equal(t, k.is(" ", actToggleFolder), true)
+		is.Equal(t, k.hint(actToggleFolder), "space")
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		_, err := newKeyMap(map[string][]string{"fly": {"f"}})
+		is.Equal(t, err != nil, true)
+	})
+
+	t.Run("actions are scoped to panes", func(t *testing.T) {
+		k, err := newKeyMap(nil)
+		is.Err(t, err, nil)
+
+		is.Equal(t, len(k.actions("r", sidebarPane)), 0)
+		is.Equal(t, k.actions("r", articlesPane)[0], actToggleRead)
+
+		// esc dismisses the error first, then goes back
+		acts := k.actions("esc", readerPane)
+		is.Equal(t, len(acts), 2)
+		is.Equal(t, acts[0], actDismiss)
+		is.Equal(t, acts[1], actBack)
+	})
+}
+
+func TestKeyOverrides(t *testing.T) {
+	cfg := &config.Config{Keys: map[string][]string{"toggle_read": {"x"}}}
+	a := newTestApp(t, cfg)
+	a.press("enter", "r")
+	is.Equal(t, a.article("1").IsRead, false)
+	a.press("x")
+	is.Equal(t, a.article("1").IsRead, true)
+
+	a.press("?")
+	is.Equal(t, a.m.overlay, helpOverlay)
+	is.Equal(t, strings.Contains(a.m.View(), "toggle read"), true)
+	a.press("?")
+	is.Equal(t, a.m.overlay, noOverlay)
+}
@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/freshrss"
+	"olexsmir.xyz/smutok/internal/render"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+type labelChangeFailedMsg struct {
+	// articles are the state before the change
+	articles []store.Article
+	labelID  string
+	action   store.Action
+	seq      int
+	err      error
+}
+
+func changeLabel(ctx context.Context, db *store.Sqlite, articles []store.Article, labelID string, action store.Action, seq int) tea.Cmd {
+	return func() tea.Msg {
+		ids := make([]string, len(articles))
+		for i, a := range articles {
+			ids[i] = a.ID
+		}
+
+		change := db.AddArticlesLabel
+		if action == store.Unlabel {
+			change = db.RemoveArticlesLabel
+		}
+		if err := change(ctx, ids, labelID); err != nil {
+			return labelChangeFailedMsg{articles: articles, labelID: labelID, action: action, seq: seq, err: err}
+		}
+		return nil
+	}
+}
+
+// applyLabel returns copy of the article with the label added or removed.
+func applyLabel(a store.Article, labelID string, action store.Action) store.Article {
+	a.Labels = slices.DeleteFunc(slices.Clone(a.Labels), func(l string) bool { return l == labelID })
+	if action == store.Label {
+		a.Labels = append(a.Labels, labelID)
+	}
+	return a
+}
+
+// setLabel adds (or removes, for [store.Unlabel]) the label to the articles,
+// same as [Model.setStatus] does with statuses.
+func (m *Model) setLabel(action store.Action, name string, articles ...store.Article) tea.Cmd {
+	labelID := freshrss.LabelID(name)
+
+	var changed []store.Article
+	for _, a := range articles {
+		if slices.Contains(a.Labels, labelID) == (action == store.Label) {
+			continue
+		}
+		m.replaceArticle(a, applyLabel(a, labelID, action))
+		changed = append(changed, a)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	// the label might be new, or its counter has changed
+	seq := m.writes.track(changed, labelID)
+	return m.queueWrite(
+		changeLabel(m.ctx, m.store, changed, labelID, action, seq),
+		loadSidebar(m.ctx, m.store),
+		loadStatus(m.ctx, m.store, m.worker),
+	)
+}
+
+// rollbackLabel restores the label of the articles, unless it was changed again.
+func (m *Model) rollbackLabel(msg labelChangeFailedMsg) {
+	for _, a := range msg.articles {
+		if !m.writes.isLatest(msg.seq, a.ID, msg.labelID) {
+			continue
+		}
+		from := m.shownArticle(applyLabel(a, msg.labelID, msg.action))
+		m.replaceArticle(from, applyLabel(from, msg.labelID, msg.action.Opposite()))
+	}
+	m.handleErr(fmt.Errorf("failed to %s as %s: %w", msg.action, freshrss.LabelName(msg.labelID), msg.err))
+}
+
+func labelsView(labels []string) string {
+	var b strings.Builder
+	for _, l := range labels {
+		b.WriteString(" #" + render.Sanitize(freshrss.LabelName(l)))
+	}
+	return b.String()
+}
+
+func labelCommand(action store.Action) func(*Model, string) (tea.Cmd, error) {
+	return func(m *Model, args string) (tea.Cmd, error) {
+		if args == "" {
+			return nil, errUsage
+		}
+		if len(m.targetArticles()) == 0 {
+			return nil, errors.New("no articles selected")
+		}
+		return m.bulkStatus(func(articles ...store.Article) tea.Cmd {
+			return m.setLabel(action, args, articles...)
+		}), nil
+	}
+}
+
+// completeLabels returns labels of the target articles.
+func completeLabels(m *Model, _ string) []string {
+	var res []string
+	for _, a := range m.targetArticles() {
+		for _, l := range a.Labels {
+			if name := freshrss.LabelName(l); !slices.Contains(res, name) {
+				res = append(res, name)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/smutok/internal/store"
+	"olexsmir.xyz/x/is"
+)
+
+func TestApplyLabel(t *testing.T) {
+	a := store.Article{ID: "1", Labels: []string{"user/-/label/go"}}
+
+	labeled := applyLabel(a, "user/-/label/later", store.Label)
+	is.Equal(t, labelsView(labeled.Labels), " #go #later")
+	is.Equal(t, labelsView(a.Labels), " #go")
+
+	unlabeled := applyLabel(labeled, "user/-/label/go", store.Unlabel)
+	is.Equal(t, strings.Join(unlabeled.Labels, ","), "user/-/label/later")
+}
+
+func TestLabelCommand(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.press("enter", ":")
+	a.typeText("label later")
+	a.press("enter")
+	is.Equal(t, labelsView(a.m.articles.articles[0].Labels), " #later")
+	is.Equal(t, labelsView(a.article("1").Labels), " #later")
+
+	a.press(":")
+	a.typeText("unlabel later")
+	a.press("enter")
+	is.Equal(t, labelsView(a.article("1").Labels), "")
+
+	a.press("u")
+	is.Equal(t, labelsView(a.m.articles.articles[0].Labels), " #later")
+	is.Equal(t, labelsView(a.article("1").Labels), " #later")
+}
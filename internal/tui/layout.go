@@ -0,0 +1,75 @@
+package tui
+
+const (
+	// narrowWidth is the terminal width below which only the focused pane is shown.
+	narrowWidth = 80
+
+	minPaneWidth = 12
+	resizeStep   = 4
+)
+
+func (m *Model) singlePane() bool { return m.width < narrowWidth }
+
+// paneWidths returns sizes of panes without borders.
+// In single pane mode every pane takes the whole width.
+func (m *Model) paneWidths() (sidebar, articles, reader int) {
+	if m.singlePane() {
+		w := max(m.width-2, 0)
+		return w, w, w
+	}
+
+	const borders = 2 * 3
+	inner := max(m.width-borders, 0)
+
+	sidebar = m.sidebarWidth
+	if sidebar == 0 {
+		sidebar = clamp(inner/5, 16, 40)
+	}
+	sidebar = clamp(sidebar, minPaneWidth, inner-2*minPaneWidth)
+
+	articles = m.articlesWidth
+	if articles == 0 {
+		articles = clamp((inner-sidebar)*2/5, 20, 80)
+	}
+	articles = clamp(articles, minPaneWidth, inner-sidebar-minPaneWidth)
+
+	reader = max(inner-sidebar-articles, 0)
+	return sidebar, articles, reader
+}
+
+// paneHeight is the height of panes without borders and footer.
+func (m *Model) paneHeight() int { return max(m.height-3, 0) }
+
+func (m *Model) layout() {
+	_, _, rw := m.paneWidths()
+	m.reader.setSize(rw, m.paneHeight())
+}
+
+// resizePane changes width of the focused pane, the reader takes up the rest.
+// Reports false if there's nothing to resize.
+func (m *Model) resizePane(delta int) bool {
+	if m.singlePane() {
+		return false
+	}
+
+	sw, aw, _ := m.paneWidths()
+	switch m.focus {
+	case sidebarPane:
+		m.sidebarWidth = sw + delta
+		m.articlesWidth = aw
+	case articlesPane:
+		m.sidebarWidth = sw
+		m.articlesWidth = aw + delta
+	default:
+		return false
+	}
+
+	// keep the widths in bounds, so resizing back works right away
+	m.sidebarWidth, m.articlesWidth, _ = m.paneWidths()
+	m.layout()
+	return true
+}
+
+func clamp(v, low, high int) int {
+	return max(low, min(v, high))
+}
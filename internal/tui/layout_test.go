@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/x/is"
+)
+
+func TestPaneWidths(t *testing.T) {
+	t.Run("single pane on narrow terminals", func(t *testing.T) {
+		m := &Model{width: 60}
+		sw, aw, rw := m.paneWidths()
+		is.Equal(t, m.singlePane(), true)
+		is.Equal(t, sw, 58)
+		is.Equal(t, aw, 58)
+		is.Equal(t, rw, 58)
+	})
+
+	t.Run("fills the width", func(t *testing.T) {
+		for _, width := range []int{80, 120, 300} {
+			m := &Model{width: width}
+			sw, aw, rw := m.paneWidths()
+			is.Equal(t, sw+aw+rw+6, width)
+		}
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		m := &Model{width: 120, focus: sidebarPane}
+		sw, aw, _ := m.paneWidths()
+
+		is.Equal(t, m.resizePane(resizeStep), true)
+		nsw, naw, _ := m.paneWidths()
+		is.Equal(t, nsw, sw+resizeStep)
+		is.Equal(t, naw, aw)
+
+		m.focus = readerPane
+		is.Equal(t, m.resizePane(resizeStep), false)
+	})
+
+	t.Run("resize is bounded", func(t *testing.T) {
+		m := &Model{width: 120, focus: articlesPane}
+		for range 100 {
+			m.resizePane(resizeStep)
+		}
+		sw, aw, rw := m.paneWidths()
+		is.Equal(t, rw, minPaneWidth)
+		is.Equal(t, sw+aw+rw+6, 120)
+
+		for range 100 {
+			m.resizePane(-resizeStep)
+		}
+		_, aw, _ = m.paneWidths()
+		is.Equal(t, aw, minPaneWidth)
+	})
+}
+
+func TestResizeKeys(t *testing.T) {
+	a := newTestApp(t, nil)
+	sw, _, _ := a.m.paneWidths()
+
+	a.press(">")
+	nsw, _, _ := a.m.paneWidths()
+	is.Equal(t, nsw, sw+resizeStep)
+
+	a.press("=")
+	nsw, _, _ = a.m.paneWidths()
+	is.Equal(t, nsw, sw)
+
+	a.send(tea.WindowSizeMsg{Width: 60, Height: 20})
+	is.Equal(t, a.m.singlePane(), true)
+}
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/render"
+)
+
+// linkPicker lists links of the opened article, the article itself is
+// the link number 0.
+type linkPicker struct {
+	links  []render.Link
+	marked map[int]bool
+	cursor int
+	offset int
+
+	// number typed so far
+	number string
+}
+
+func newLinkPicker(r reader) linkPicker {
+	var links []render.Link
+	if r.article != nil && r.article.Href != "" {
+		links = append(links, render.Link{Index: 0, Text: "article", URL: r.article.Href})
+	}
+	links = append(links, r.doc.Links...)
+	return linkPicker{links: links, marked: make(map[int]bool)}
+}
+
+func (p *linkPicker) move(delta int) {
+	p.cursor = clamp(p.cursor+delta, 0, max(len(p.links)-1, 0))
+}
+
+func (p *linkPicker) toggleMark() {
+	if len(p.links) == 0 {
+		return
+	}
+	p.marked[p.cursor] = !p.marked[p.cursor]
+	p.move(1)
+}
+
+// typeDigit moves the cursor to the link with typed number,
+// reports true if no other link number starts with it.
+func (p *linkPicker) typeDigit(d string) bool {
+	number := p.number + d
+	i := p.find(number)
+	if i == -1 {
+		number = d
+		if i = p.find(number); i == -1 {
+			p.number = ""
+			return false
+		}
+	}
+
+	p.number = number
+	p.cursor = i
+	for _, l := range p.links {
+		s := strconv.Itoa(l.Index)
+		if s != number && strings.HasPrefix(s, number) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *linkPicker) find(number string) int {
+	for i, l := range p.links {
+		if strconv.Itoa(l.Index) == number {
+			return i
+		}
+	}
+	return -1
+}
+
+// urls returns urls of marked links, or the selected one if none are marked.
+func (p *linkPicker) urls() []string {
+	var urls []string
+	for i, l := range p.links {
+		if p.marked[i] {
+			urls = append(urls, l.URL)
+		}
+	}
+	if len(urls) == 0 && len(p.links) > 0 {
+		urls = append(urls, p.links[p.cursor].URL)
+	}
+	return urls
+}
+
+func (m *Model) openLinkPicker() {
+	m.links = newLinkPicker(m.reader)
+	m.overlay = linksOverlay
+}
+
+func (m *Model) updateLinkPicker(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	switch {
+	case len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+		if m.links.typeDigit(key) {
+			m.overlay = noOverlay
+			return m.openURLs(m.links.urls()...)
+		}
+		return nil
+	case key == " ":
+		m.links.toggleMark()
+	case m.keys.is(key, actDown):
+		m.links.move(1)
+	case m.keys.is(key, actUp):
+		m.links.move(-1)
+	case m.keys.is(key, actTop):
+		m.links.move(-len(m.links.links))
+	case m.keys.is(key, actBottom):
+		m.links.move(len(m.links.links))
+	case m.keys.is(key, actOpen):
+		m.overlay = noOverlay
+		return m.openURLs(m.links.urls()...)
+	case key == "esc", m.keys.is(key, actQuit), m.keys.is(key, actLinks):
+		m.overlay = noOverlay
+	}
+	m.links.number = ""
+	return nil
+}
+
+func (m *Model) linkPickerView(width, height int) string {
+	p := &m.links
+	if len(p.links) == 0 {
+		return mutedStyle.Render("no links")
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Links"))
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("  number: open, space: mark, %s: open marked", m.keys.hint(actOpen))))
+
+	height = max(height-1, 0)
+	p.offset = scrollOffset(p.offset, p.cursor, height, len(p.links))
+	for i := p.offset; i < min(p.offset+height, len(p.links)); i++ {
+		l := p.links[i]
+
+		mark := "  "
+		if p.marked[i] {
+			mark = "+ "
+		}
+
+		text := fmt.Sprintf("%s[%d] ", mark, l.Index)
+		if l.Text != "" && l.Text != l.URL {
+			text += l.Text + " "
+		}
+
+		url := render.Sanitize(l.URL)
+		line := truncate(text+mutedStyle.Render(url), width)
+		if i == p.cursor {
+			line = selectedStyle.Width(width).Render(truncate(text+url, width))
+		}
+
+		b.WriteByte('\n')
+		b.WriteString(line)
+	}
+	return b.String()
+}
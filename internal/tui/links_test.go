@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/smutok/internal/config"
+	"olexsmir.xyz/smutok/internal/render"
+	"olexsmir.xyz/smutok/internal/store"
+	"olexsmir.xyz/x/is"
+)
+
+func TestLinkPicker(t *testing.T) {
+	r := reader{
+		article: &store.Article{Href: "https://a/0"},
+		doc:     render.Document{},
+	}
+	for i := 1; i <= 12; i++ {
+		r.doc.Links = append(r.doc.Links, render.Link{Index: i, URL: "https://a/" + strings.Repeat("x", i)})
+	}
+
+	t.Run("includes the article", func(t *testing.T) {
+		p := newLinkPicker(r)
+		is.Equal(t, len(p.links), 13)
+		is.Equal(t, strings.Join(p.urls(), ","), "https://a/0")
+	})
+
+	t.Run("single digit", func(t *testing.T) {
+		p := newLinkPicker(r)
+		is.Equal(t, p.typeDigit("5"), true)
+		is.Equal(t, p.links[p.cursor].Index, 5)
+	})
+
+	t.Run("ambiguous number waits for more digits", func(t *testing.T) {
+		p := newLinkPicker(r)
+		is.Equal(t, p.typeDigit("1"), false)
+		is.Equal(t, p.links[p.cursor].Index, 1)
+		is.Equal(t, p.typeDigit("2"), true)
+		is.Equal(t, p.links[p.cursor].Index, 12)
+	})
+
+	t.Run("marked links", func(t *testing.T) {
+		p := newLinkPicker(r)
+		p.move(1)
+		p.toggleMark()
+		p.toggleMark()
+		is.Equal(t, strings.Join(p.urls(), ","), "https://a/x,https://a/xx")
+	})
+}
+
+func TestOpenOnlyWebLinks(t *testing.T) {
+	o := opener{args: []string{"true"}}
+	for _, url := range []string{"file:///etc/passwd", "--help", "mailto:a@a.com", "https://a.com/\x1b"} {
+		msg, ok := o.open("https://a.com", url)().(errMsg)
+		is.Equal(t, ok, true)
+		is.Equal(t, strings.HasPrefix(msg.err.Error(), "refusing to open"), true)
+	}
+}
+
+func TestLinkPickerKeys(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Open.Command = "true"
+	a := newTestApp(t, cfg)
+	a.press("enter", "enter", "o")
+	is.Equal(t, a.m.overlay, linksOverlay)
+
+	a.press("enter")
+	is.Equal(t, a.m.overlay, noOverlay)
+	is.Equal(t, a.m.toast, "opening https://a.com/1")
+	is.Equal(t, a.m.err, nil)
+}
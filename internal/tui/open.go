@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/config"
+	"olexsmir.xyz/smutok/internal/render"
+)
+
+// opener opens urls with the configured command.
+type opener struct {
+	args []string
+
+	// terminal is set when the command runs in the terminal,
+	// so the tui has to be suspended while it's running.
+	terminal bool
+}
+
+func newOpener(cfg *config.Config) opener {
+	command := cfg.Open.Command
+	if command == "" {
+		command = os.Getenv("BROWSER")
+	}
+	if command == "" {
+		command = "xdg-open"
+	}
+	return opener{
+		args:     strings.Fields(command),
+		terminal: cfg.Open.Terminal,
+	}
+}
+
+func (o opener) command(url string) *exec.Cmd {
+	args := append(o.args[1:len(o.args):len(o.args)], url)
+	return exec.Command(o.args[0], args...)
+}
+
+// open opens the urls one by one, in the background unless it's a terminal command.
+// Only web links are opened, others could be local files, or start with "-"
+// and be taken for an option of the command.
+func (o opener) open(urls ...string) tea.Cmd {
+	if len(urls) == 0 || len(o.args) == 0 {
+		return nil
+	}
+	for _, url := range urls {
+		if !render.IsWebURL(url) {
+			return func() tea.Msg {
+				return errMsg{fmt.Errorf("refusing to open %s, only http and https links are opened", url)}
+			}
+		}
+	}
+
+	if o.terminal {
+		cmds := make([]tea.Cmd, len(urls))
+		for i, url := range urls {
+			cmds[i] = tea.ExecProcess(o.command(url), func(err error) tea.Msg {
+				if err != nil {
+					return errMsg{fmt.Errorf("failed to open %s: %w", url, err)}
+				}
+				return nil
+			})
+		}
+		return tea.Sequence(cmds...)
+	}
+
+	return func() tea.Msg {
+		for _, url := range urls {
+			cmd := o.command(url)
+			if err := cmd.Start(); err != nil {
+				return errMsg{fmt.Errorf("failed to open %s: %w", url, err)}
+			}
+			go cmd.Wait() // the browser may outlive the command, only reap it
+		}
+		return nil
+	}
+}
+
+// openURLs opens the urls and tells the user about it.
+func (m *Model) openURLs(urls ...string) tea.Cmd {
+	open := m.opener.open(urls...)
+	if open == nil {
+		return nil
+	}
+
+	text := "opening " + urls[0]
+	if len(urls) > 1 {
+		text = fmt.Sprintf("opening %d links", len(urls))
+	}
+	return tea.Batch(m.showToast(text), open)
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// option is a setting that can be changed with ":set name=value",
+// changes are kept until restart.
+type option struct {
+	name string
+	get  func(m *Model) string
+	set  func(m *Model, value string) error
+}
+
+func boolOption(name string, field func(m *Model) *bool) option {
+	return option{
+		name: name,
+		get:  func(m *Model) string { return strconv.FormatBool(*field(m)) },
+		set: func(m *Model, value string) error {
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s: expected true or false", name)
+			}
+			*field(m) = v
+			return nil
+		},
+	}
+}
+
+func intOption(name string, field func(m *Model) *int) option {
+	return option{
+		name: name,
+		get:  func(m *Model) string { return strconv.Itoa(*field(m)) },
+		set: func(m *Model, value string) error {
+			v, err := strconv.Atoi(value)
+			if err != nil || v < 0 {
+				return fmt.Errorf("%s: expected a non-negative number", name)
+			}
+			*field(m) = v
+			return nil
+		},
+	}
+}
+
+func defaultOptions() []option {
+	return []option{
+		boolOption("read.on_open", func(m *Model) *bool { return &m.cfg.Read.OnOpen }),
+		boolOption("read.on_scroll", func(m *Model) *bool { return &m.cfg.Read.OnScroll }),
+		intOption("read.after_seconds", func(m *Model) *int { return &m.cfg.Read.AfterSeconds }),
+		{
+			name: "open.command",
+			get:  func(m *Model) string { return m.cfg.Open.Command },
+			set: func(m *Model, value string) error {
+				m.cfg.Open.Command = value
+				return nil
+			},
+		},
+		boolOption("open.terminal", func(m *Model) *bool { return &m.cfg.Open.Terminal }),
+		intOption("sidebar_width", func(m *Model) *int { return &m.sidebarWidth }),
+		intOption("articles_width", func(m *Model) *int { return &m.articlesWidth }),
+	}
+}
+
+func completeSet(m *Model, _ string) []string {
+	options := defaultOptions()
+	res := make([]string, len(options))
+	for i, o := range options {
+		res[i] = o.name + "=" + o.get(m)
+	}
+	return res
+}
+
+// cmdSet sets the option, or shows its value if there's no value.
+func cmdSet(m *Model, args string) (tea.Cmd, error) {
+	name, value, hasValue := strings.Cut(args, "=")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if name == "" {
+		return nil, errUsage
+	}
+
+	options := defaultOptions()
+	i := slices.IndexFunc(options, func(o option) bool { return o.name == name })
+	if i == -1 {
+		return nil, fmt.Errorf("unknown option: %s", name)
+	}
+
+	o := options[i]
+	if !hasValue {
+		return m.showToast(o.name + "=" + o.get(m)), nil
+	}
+
+	if err := o.set(m, value); err != nil {
+		return nil, err
+	}
+	m.opener = newOpener(m.cfg)
+	m.layout()
+	return m.showToast(o.name + "=" + o.get(m)), nil
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type promptKind int
+
+const (
+	articlesSearchPrompt promptKind = iota
+	readerSearchPrompt
+	fullSearchPrompt
+	commandPrompt
+	subscribePrompt
+	renamePrompt
+	movePrompt
+	unsubscribePrompt
+	deleteFolderPrompt
+)
+
+// prompt is a line input shown in the footer,
+// the value is applied while it's being typed.
+type prompt struct {
+	kind  promptKind
+	input textinput.Model
+
+	// prev is the value before the prompt was opened, it's restored on cancel
+	prev string
+
+	// target is id of the item the prompt is about, if any
+	target string
+}
+
+func newPrompt(kind promptKind, prefix, value string) *prompt {
+	input := textinput.New()
+	input.Prompt = prefix
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.SetValue(value)
+	input.Focus()
+	input.CursorEnd()
+	return &prompt{kind: kind, input: input, prev: value}
+}
+
+func (m *Model) openPrompt(kind promptKind) {
+	switch kind {
+	case articlesSearchPrompt:
+		m.prompt = newPrompt(kind, "/", m.articles.query)
+	case readerSearchPrompt:
+		m.prompt = newPrompt(kind, "/", m.reader.query)
+	case fullSearchPrompt:
+		m.prompt = newPrompt(kind, "search: ", m.search.query)
+	case commandPrompt:
+		m.prompt = newPrompt(kind, ":", "")
+		m.prompt.input.ShowSuggestions = true
+		m.prompt.input.SetSuggestions(m.completeCommand(""))
+	case subscribePrompt:
+		m.prompt = newPrompt(kind, "subscribe to (url [folder]): ", "")
+		m.prompt.input.ShowSuggestions = true
+	}
+}
+
+// openCommand opens the command prompt with the line already typed.
+func (m *Model) openCommand(line string) {
+	m.openPrompt(commandPrompt)
+	m.prompt.input.SetValue(line)
+	m.prompt.input.CursorEnd()
+	m.prompt.input.SetSuggestions(m.completeCommand(line))
+}
+
+func (m *Model) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	p := m.prompt
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.prompt = nil
+		return m.applyPrompt(p.kind, p.prev)
+	case tea.KeyEnter:
+		m.prompt = nil
+		return m.submitPrompt(p, p.input.Value())
+	}
+
+	prev := p.input.Value()
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() == prev {
+		return cmd
+	}
+	return tea.Batch(cmd, m.applyPrompt(p.kind, p.input.Value()))
+}
+
+// submitPrompt handles the value of prompts that aren't applied while typing.
+func (m *Model) submitPrompt(p *prompt, value string) tea.Cmd {
+	value = strings.TrimSpace(value)
+	switch p.kind {
+	case fullSearchPrompt:
+		if value == "" {
+			return nil
+		}
+		return runSearch(m.ctx, m.store, value)
+	case commandPrompt:
+		return m.runCommand(value)
+	case subscribePrompt:
+		return m.runCommand("add " + value)
+	case renamePrompt:
+		if value == "" || value == p.prev {
+			return nil
+		}
+		return m.renameFeed(p.target, p.prev, value)
+	case movePrompt:
+		if value == "" {
+			return nil
+		}
+		return m.moveFeed(p.target, m.feedTitle(p.target), value)
+	case unsubscribePrompt:
+		if !strings.EqualFold(value, "y") {
+			return nil
+		}
+		return m.unsubscribe(p.target, m.feedTitle(p.target))
+	case deleteFolderPrompt:
+		if !strings.EqualFold(value, "y") {
+			return nil
+		}
+		return m.deleteFolder(p.target)
+	}
+	return nil
+}
+
+// applyPrompt applies the value of prompts while it's being typed.
+func (m *Model) applyPrompt(kind promptKind, value string) tea.Cmd {
+	switch kind {
+	case articlesSearchPrompt:
+		return m.articles.setQuery(m.ctx, m.store, value)
+	case readerSearchPrompt:
+		m.reader.search(value)
+	case commandPrompt:
+		if m.prompt != nil {
+			m.prompt.input.SetSuggestions(m.completeCommand(value))
+		}
+	case subscribePrompt:
+		if m.prompt != nil {
+			m.prompt.input.SetSuggestions(completeAdd(m, value))
+		}
+	}
+	return nil
+}
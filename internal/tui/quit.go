@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// flushTimeout is how long quitting waits for pending actions to be pushed.
+const flushTimeout = 10 * time.Second
+
+type flushedMsg struct {
+	// pending is number of actions that are still queued
+	pending int
+	err     error
+}
+
+// quit saves the session, and pushes pending actions before quitting.
+// Changes that aren't saved yet are saved first, quit is retried after them.
+func (m *Model) quit() tea.Cmd {
+	if m.writes.busy {
+		m.writes.quit = true
+		m.flushing = len(m.writes.pending) + 1
+		return nil
+	}
+
+	m.saveSession()
+
+	n, err := m.store.CountPendingActions(m.ctx)
+	if err != nil {
+		slog.Error("failed to count pending actions", "err", err)
+	}
+	if n == 0 {
+		m.isQutting = true
+		return tea.Quit
+	}
+
+	m.flushing = n
+	ctx, worker, db := m.ctx, m.worker, m.store
+	return func() tea.Msg {
+		fctx, cancel := context.WithTimeout(ctx, flushTimeout)
+		defer cancel()
+
+		err := worker.Flush(fctx)
+		if err == nil {
+			return flushedMsg{}
+		}
+
+		pending, cerr := db.CountPendingActions(ctx)
+		if cerr != nil {
+			pending = n
+		}
+		return flushedMsg{pending: pending, err: err}
+	}
+}
+
+func (m *Model) finishFlush(msg flushedMsg) tea.Cmd {
+	if msg.err != nil {
+		slog.Error("failed to push actions on quit", "err", msg.err)
+		m.warning = fmt.Sprintf("%d actions are still queued locally, they'll be pushed next time (%v)", msg.pending, msg.err)
+	}
+
+	m.isQutting = true
+	return tea.Quit
+}
+
+// updateFlush handles keys while actions are pushed, quit doesn't wait for them.
+func (m *Model) updateFlush(msg tea.KeyMsg) tea.Cmd {
+	if !m.keys.is(msg.String(), actQuit) {
+		return nil
+	}
+	return m.finishFlush(flushedMsg{pending: m.flushing, err: errors.New("interrupted")})
+}
+
+func (m *Model) flushStatus() string {
+	return fmt.Sprintf("pushing %d actions…", m.flushing)
+}
+
+// Warning returns a warning that should be shown after the tui is closed, if any.
+func (m *Model) Warning() string { return m.warning }
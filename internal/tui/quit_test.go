@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/x/is"
+)
+
+func TestFlushOnQuit(t *testing.T) {
+	keys, err := newKeyMap(nil)
+	is.Err(t, err, nil)
+
+	t.Run("pushed", func(t *testing.T) {
+		m := &Model{keys: keys, flushing: 3}
+		is.Equal(t, m.flushStatus(), "pushing 3 actions…")
+
+		m.finishFlush(flushedMsg{})
+		is.Equal(t, m.isQutting, true)
+		is.Equal(t, m.Warning(), "")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		m := &Model{keys: keys, flushing: 3}
+		m.finishFlush(flushedMsg{pending: 2, err: errors.New("timeout")})
+		is.Equal(t, m.Warning(), "2 actions are still queued locally, they'll be pushed next time (timeout)")
+	})
+
+	t.Run("quit doesn't wait", func(t *testing.T) {
+		m := &Model{keys: keys, flushing: 3}
+		is.Equal(t, m.updateFlush(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}) == nil, true)
+		is.Equal(t, m.isQutting, false)
+
+		m.updateFlush(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+		is.Equal(t, m.isQutting, true)
+		is.Equal(t, m.Warning(), "3 actions are still queued locally, they'll be pushed next time (interrupted)")
+	})
+
+	t.Run("pushes pending actions", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("enter", "r", "q")
+		is.Equal(t, a.worker.flushed, 1)
+		is.Equal(t, a.quit, true)
+	})
+
+	t.Run("nothing to push", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("q")
+		is.Equal(t, a.worker.flushed, 0)
+		is.Equal(t, a.quit, true)
+	})
+
+	t.Run("waits for unsaved changes", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("enter")
+
+		_, first := a.m.Update(testKey("r"))
+		a.press("j", "r", "q")
+		is.Equal(t, a.quit, false)
+		is.Equal(t, a.m.flushStatus(), "pushing 2 actions…")
+
+		a.run(first)
+		is.Equal(t, a.article("1").IsRead, true)
+		is.Equal(t, a.article("2").IsRead, true)
+		is.Equal(t, a.worker.flushed, 1)
+		is.Equal(t, a.quit, true)
+	})
+}
@@ -0,0 +1,212 @@
+package tui
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"olexsmir.xyz/smutok/internal/render"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+type reader struct {
+	article  *store.Article
+	doc      render.Document
+	content  string
+	viewport viewport.Model
+
+	// query is highlighted in the content, matches are its line numbers
+	query   string
+	matches []int
+}
+
+func newReader() reader {
+	return reader{viewport: viewport.New(0, 0)}
+}
+
+func (r *reader) setArticle(a store.Article) {
+	r.article = &a
+	r.render()
+	r.viewport.GotoTop()
+}
+
+// replace updates statuses and labels of the article, if it's the opened one.
+func (r *reader) replace(a store.Article) {
+	if r.article == nil || r.article.ID != a.ID {
+		return
+	}
+	r.article.IsRead = a.IsRead
+	r.article.IsStarred = a.IsStarred
+	if !slices.Equal(r.article.Labels, a.Labels) {
+		r.article.Labels = a.Labels
+		r.render()
+	}
+}
+
+func (r *reader) setSize(width, height int) {
+	r.viewport.Height = height
+	if r.viewport.Width == width {
+		return
+	}
+	r.viewport.Width = width
+	r.render()
+}
+
+func (r *reader) render() {
+	if r.article == nil {
+		r.viewport.SetContent("")
+		return
+	}
+
+	width := r.viewport.Width
+	doc, err := render.Render(r.article.Content, r.article.Href, width)
+	if err != nil {
+		slog.Error("failed to render article", "id", r.article.ID, "err", err)
+		doc = render.Document{Text: lipgloss.NewStyle().Width(width).Render(r.article.Content)}
+	}
+	r.doc = doc
+
+	var meta []string
+	if r.article.FeedTitle != "" {
+		meta = append(meta, r.article.FeedTitle)
+	}
+	if r.article.Author != "" {
+		meta = append(meta, r.article.Author)
+	}
+	if r.article.PublishedAt != 0 {
+		meta = append(meta, time.Unix(r.article.PublishedAt, 0).Format("2006-01-02 15:04"))
+	}
+	if len(r.article.Labels) > 0 {
+		meta = append(meta, strings.TrimSpace(labelsView(r.article.Labels)))
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Width(width).Render(r.article.Title))
+	b.WriteByte('\n')
+	b.WriteString(mutedStyle.Width(width).Render(strings.Join(meta, " · ")))
+	b.WriteString("\n\n")
+	b.WriteString(r.doc.Text)
+
+	r.content = b.String()
+	r.highlight()
+}
+
+// search highlights the query, and scrolls to the first match
+// if none of them are visible.
+func (r *reader) search(query string) {
+	r.query = query
+	r.highlight()
+
+	y := r.viewport.YOffset
+	for _, line := range r.matches {
+		if line >= y && line < y+r.viewport.Height {
+			return
+		}
+	}
+	r.jumpMatch(1)
+}
+
+// highlight sets the content with matches of the query highlighted.
+func (r *reader) highlight() {
+	r.matches = r.matches[:0]
+	if r.query == "" {
+		r.viewport.SetContent(r.content)
+		return
+	}
+
+	lines := strings.Split(r.content, "\n")
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		start, end := indexFold(plain, r.query)
+		if start == -1 {
+			continue
+		}
+		r.matches = append(r.matches, i)
+
+		// styles of the line are dropped, so highlights aren't mixed with them
+		var b strings.Builder
+		for start != -1 {
+			b.WriteString(plain[:start])
+			b.WriteString(matchStyle.Render(plain[start:end]))
+			plain = plain[end:]
+			start, end = indexFold(plain, r.query)
+		}
+		b.WriteString(plain)
+		lines[i] = b.String()
+	}
+	r.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// jumpMatch scrolls to the next or previous line with a match, wrapping around.
+func (r *reader) jumpMatch(delta int) bool {
+	if len(r.matches) == 0 {
+		return false
+	}
+
+	y := r.viewport.YOffset
+	if delta > 0 {
+		target := r.matches[0]
+		for _, line := range r.matches {
+			if line > y {
+				target = line
+				break
+			}
+		}
+		r.viewport.SetYOffset(target)
+		if r.viewport.YOffset == y {
+			// the rest of matches are already visible at the bottom
+			r.viewport.SetYOffset(r.matches[0])
+		}
+		return true
+	}
+
+	target := r.matches[len(r.matches)-1]
+	for i := len(r.matches) - 1; i >= 0; i-- {
+		if r.matches[i] < y {
+			target = r.matches[i]
+			break
+		}
+	}
+	r.viewport.SetYOffset(target)
+	return true
+}
+
+func (r *reader) view() string {
+	if r.article == nil {
+		return mutedStyle.Render(truncate("no article selected", r.viewport.Width))
+	}
+	return r.viewport.View()
+}
+
+// indexFold returns byte offsets of the first match of substr in s,
+// ignoring case, or -1 if there's none. Unlike searching in the lowered s,
+// offsets are right even if lowering changes lengths of runes.
+func indexFold(s, substr string) (start, end int) {
+	if substr == "" {
+		return -1, -1
+	}
+
+	for i := range s {
+		j, n := i, 0
+		for _, r := range substr {
+			if j >= len(s) {
+				break
+			}
+			sr, size := utf8.DecodeRuneInString(s[j:])
+			if !strings.EqualFold(string(sr), string(r)) {
+				break
+			}
+			j += size
+			n++
+		}
+		if n == utf8.RuneCountInString(substr) {
+			return i, j
+		}
+	}
+	return -1, -1
+}
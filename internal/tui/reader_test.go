@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"olexsmir.xyz/x/is"
+)
+
+func TestReaderSearch(t *testing.T) {
+	r := newReader()
+	r.viewport.Width = 20
+	r.viewport.Height = 2
+	r.content = strings.Join([]string{"one", "Two", "three", "four", "two two"}, "\n")
+
+	r.search("two")
+	is.Equal(t, len(r.matches), 2)
+	is.Equal(t, r.viewport.YOffset, 0) // the first match is visible
+	is.Equal(t, strings.Contains(ansi.Strip(r.viewport.View()), "Two"), true)
+
+	is.Equal(t, r.jumpMatch(1), true)
+	is.Equal(t, r.viewport.YOffset, 1)
+
+	is.Equal(t, r.jumpMatch(1), true)
+	is.Equal(t, r.viewport.YOffset, 3) // clamped to the bottom
+
+	is.Equal(t, r.jumpMatch(1), true)
+	is.Equal(t, r.viewport.YOffset, 1) // wrapped around
+
+	is.Equal(t, r.jumpMatch(-1), true)
+	is.Equal(t, r.viewport.YOffset, 3)
+
+	r.search("")
+	is.Equal(t, r.jumpMatch(1), false)
+}
+
+func TestReaderSearchFolding(t *testing.T) {
+	r := newReader()
+	r.viewport.Width = 40
+	r.viewport.Height = 5
+	// "İ" is longer lowered, and "K" (kelvin sign) is shorter
+	r.content = strings.Join([]string{"İstanbul ünicode", "plain", "\u212A ok"}, "\n")
+
+	r.search("ÜNI")
+	is.Equal(t, len(r.matches), 1)
+	is.Equal(t, strings.Contains(r.viewport.View(), matchStyle.Render("üni")), true)
+
+	r.search("k")
+	is.Equal(t, r.matches, []int{2})
+	is.Equal(t, strings.Contains(r.viewport.View(), matchStyle.Render("\u212A")), true)
+}
+
+func TestIndexFold(t *testing.T) {
+	for _, tt := range []struct {
+		s, substr  string
+		start, end int
+	}{
+		{"Hello", "LL", 2, 4},
+		{"İstanbul", "stan", 2, 6},
+		{"straße", "SSE", -1, -1},
+		{"abc", "", -1, -1},
+		{"ab", "abc", -1, -1},
+	} {
+		start, end := indexFold(tt.s, tt.substr)
+		is.Equal(t, start, tt.start)
+		is.Equal(t, end, tt.end)
+	}
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+type readTimerMsg struct {
+	id        int
+	articleID string
+}
+
+// autoRead marks the articles as read, as one change, so one undo reverts it.
+// Articles excluded by the config are skipped.
+func (m *Model) autoRead(articles ...store.Article) tea.Cmd {
+	var read []store.Article
+	for _, a := range articles {
+		if !a.IsRead && !m.autoReadDisabled(a) {
+			read = append(read, a)
+		}
+	}
+	return m.setStatus(store.Read, read...)
+}
+
+func (m *Model) autoReadDisabled(a store.Article) bool {
+	return slices.ContainsFunc(m.cfg.Read.ExcludeFeeds, func(feed string) bool {
+		return feed == a.FeedID || feed == a.FeedTitle
+	})
+}
+
+// readOnOpen marks the article opened in the reader as read, right away
+// or after it's been visible for a while, depending on the config.
+func (m *Model) readOnOpen(a store.Article) tea.Cmd {
+	if m.cfg.Read.OnOpen {
+		return m.autoRead(a)
+	}
+
+	if m.cfg.Read.AfterSeconds <= 0 || a.IsRead || m.autoReadDisabled(a) {
+		return nil
+	}
+
+	m.readTimerID++
+	msg := readTimerMsg{id: m.readTimerID, articleID: a.ID}
+	return tea.Tick(time.Duration(m.cfg.Read.AfterSeconds)*time.Second, func(time.Time) tea.Msg {
+		return msg
+	})
+}
+
+func (m *Model) readTimerExpired(msg readTimerMsg) tea.Cmd {
+	if msg.id != m.readTimerID || !m.readerVisible() ||
+		m.reader.article == nil || m.reader.article.ID != msg.articleID {
+		return nil
+	}
+	return m.autoRead(*m.reader.article)
+}
+
+func (m *Model) readerVisible() bool {
+	return m.overlay == noOverlay && (!m.singlePane() || m.focus == readerPane)
+}
+
+// readPassed marks articles the list cursor has moved past as read.
+func (m *Model) readPassed(prev int) tea.Cmd {
+	if !m.cfg.Read.OnScroll {
+		return nil
+	}
+
+	end := min(m.articles.cursor, len(m.articles.articles))
+	if prev >= end {
+		return nil
+	}
+	return m.autoRead(m.articles.articles[prev:end]...)
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"testing"
+
+	"olexsmir.xyz/smutok/internal/config"
+	"olexsmir.xyz/smutok/internal/store"
+	"olexsmir.xyz/x/is"
+)
+
+func newReadPolicyModel(t *testing.T) *Model {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Read.OnScroll = true
+	cfg.Read.ExcludeFeeds = []string{"feed/2"}
+
+	return &Model{
+		cfg: cfg,
+		articles: articleList{articles: []store.Article{
+			{ID: "1", FeedID: "feed/1"},
+			{ID: "2", FeedID: "feed/2"},
+			{ID: "3", FeedID: "feed/1"},
+			{ID: "4", FeedID: "feed/1"},
+		}},
+	}
+}
+
+func readIDs(l articleList) string {
+	var ids string
+	for _, a := range l.articles {
+		if a.IsRead {
+			ids += a.ID
+		}
+	}
+	return ids
+}
+
+func TestReadPassed(t *testing.T) {
+	t.Run("marks passed articles", func(t *testing.T) {
+		m := newReadPolicyModel(t)
+		m.articles.move(3)
+		is.Equal(t, m.readPassed(0) != nil, true)
+		is.Equal(t, readIDs(m.articles), "13")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		m := newReadPolicyModel(t)
+		m.cfg.Read.OnScroll = false
+		m.articles.move(3)
+		is.Equal(t, m.readPassed(0) == nil, true)
+		is.Equal(t, readIDs(m.articles), "")
+	})
+
+	t.Run("one change, one undo", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.Read.OnScroll = true
+		a := newTestApp(t, cfg)
+
+		a.press("enter", "G")
+		is.Equal(t, a.listed(), "r1 r2 3 ")
+		is.Equal(t, a.article("2").IsRead, true)
+
+		a.press("u")
+		is.Equal(t, a.listed(), "1 2 3 ")
+		is.Equal(t, a.article("1").IsRead, false)
+	})
+}
+
+func TestReadAfterSeconds(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Read.AfterSeconds = 3
+	a := newTestApp(t, cfg)
+
+	a.press("enter", "enter")
+	first := readTimerMsg{id: a.m.readTimerID, articleID: "1"}
+	is.Equal(t, a.article("1").IsRead, false)
+
+	a.press("h", "j", "enter")
+	a.send(first) // another article was opened since
+	is.Equal(t, a.article("1").IsRead, false)
+
+	a.send(readTimerMsg{id: a.m.readTimerID, articleID: "2"})
+	is.Equal(t, a.article("2").IsRead, true)
+	is.Equal(t, a.listed(), "1 r2 3 ")
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/render"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+const searchLimit = 200
+
+// searchResults are results of the full-text search over all articles.
+type searchResults struct {
+	query   string
+	results []store.SearchResult
+	cursor  int
+	offset  int
+}
+
+type searchResultsMsg struct {
+	query   string
+	results []store.SearchResult
+}
+
+func runSearch(ctx context.Context, db *store.Sqlite, query string) tea.Cmd {
+	return func() tea.Msg {
+		results, err := db.Search(ctx, query, searchLimit)
+		if err != nil {
+			return errMsg{fmt.Errorf("search failed: %w", err)}
+		}
+		for i := range results {
+			results[i].Article = sanitizeArticle(results[i].Article)
+		}
+		return searchResultsMsg{query: query, results: results}
+	}
+}
+
+func (m *Model) showSearchResults(msg searchResultsMsg) {
+	m.search = searchResults{query: msg.query, results: msg.results}
+	m.overlay = searchOverlay
+}
+
+func (m *Model) updateSearchResults(msg tea.KeyMsg) tea.Cmd {
+	s := &m.search
+	key := msg.String()
+	switch {
+	case m.keys.is(key, actDown):
+		s.cursor = clamp(s.cursor+1, 0, max(len(s.results)-1, 0))
+	case m.keys.is(key, actUp):
+		s.cursor = clamp(s.cursor-1, 0, max(len(s.results)-1, 0))
+	case m.keys.is(key, actTop):
+		s.cursor = 0
+	case m.keys.is(key, actBottom):
+		s.cursor = max(len(s.results)-1, 0)
+	case m.keys.is(key, actOpen):
+		if s.cursor >= len(s.results) {
+			return nil
+		}
+		m.overlay = noOverlay
+		m.focus = readerPane
+		return loadArticle(m.ctx, m.store, s.results[s.cursor].ID)
+	case key == "esc", m.keys.is(key, actQuit), m.keys.is(key, actFullSearch):
+		m.overlay = noOverlay
+	}
+	return nil
+}
+
+var snippetHighlighter = strings.NewReplacer(store.HighlightStart, "", store.HighlightEnd, "")
+
+func (m *Model) searchResultsView(width, height int) string {
+	s := &m.search
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(truncate(fmt.Sprintf("Search: %s (%d)", s.query, len(s.results)), width)))
+	if len(s.results) == 0 {
+		b.WriteString("\n" + mutedStyle.Render("nothing found"))
+		return b.String()
+	}
+
+	// every result takes two lines
+	rows := max((height-1)/2, 1)
+	s.offset = scrollOffset(s.offset, s.cursor, rows, len(s.results))
+	for i := s.offset; i < min(s.offset+rows, len(s.results)); i++ {
+		r := s.results[i]
+
+		meta := "  " + r.FeedTitle + " · " + formatDate(r.PublishedAt)
+		title := truncate(articleFlags(r.Article)+r.Title, max(width-len([]rune(meta)), 0))
+		line := title + mutedStyle.Render(meta)
+		if i == s.cursor {
+			line = selectedStyle.Width(width).Render(truncate(title+meta, width))
+		}
+
+		b.WriteByte('\n')
+		b.WriteString(truncate(line, width))
+		b.WriteByte('\n')
+		b.WriteString(highlightSnippet(truncate(r.Snippet, width)))
+	}
+	return b.String()
+}
+
+// highlightSnippet replaces match markers of the snippet with the style.
+func highlightSnippet(snippet string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(snippet, store.HighlightStart)
+		if start == -1 {
+			break
+		}
+		end := strings.Index(snippet[start:], store.HighlightEnd)
+		if end == -1 {
+			break
+		}
+		end += start
+
+		b.WriteString(mutedStyle.Render(render.Sanitize(snippet[:start])))
+		b.WriteString(matchStyle.Render(render.Sanitize(snippet[start+len(store.HighlightStart) : end])))
+		snippet = snippet[end+len(store.HighlightEnd):]
+	}
+	b.WriteString(mutedStyle.Render(render.Sanitize(snippetHighlighter.Replace(snippet))))
+	return b.String()
+}
@@ -0,0 +1,21 @@
+package tui
+
+import (
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestFullSearch(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.press("S")
+	a.typeText("second")
+	a.press("enter")
+	is.Equal(t, a.m.overlay, searchOverlay)
+	is.Equal(t, len(a.m.search.results), 1)
+
+	a.press("enter")
+	is.Equal(t, a.m.overlay, noOverlay)
+	is.Equal(t, a.m.focus, readerPane)
+	is.Equal(t, a.m.reader.article.ID, "2")
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+// selection is a set of articles in the list that bulk actions apply to.
+// Articles are marked one by one, or as a range in visual mode.
+type selection struct {
+	marked map[string]bool
+
+	// anchor is the id of the article where visual mode started,
+	// the range from it to the cursor is selected, empty if it's off
+	anchor string
+}
+
+func (l *articleList) visual() bool { return l.selection.anchor != "" }
+
+// toggleMark marks or unmarks the article under the cursor.
+func (l *articleList) toggleMark() {
+	a, ok := l.selected()
+	if !ok {
+		return
+	}
+
+	if l.selection.marked == nil {
+		l.selection.marked = make(map[string]bool)
+	}
+	if l.selection.marked[a.ID] {
+		delete(l.selection.marked, a.ID)
+	} else {
+		l.selection.marked[a.ID] = true
+	}
+}
+
+// toggleVisual starts visual mode, or ends it keeping the range marked.
+func (l *articleList) toggleVisual() {
+	if l.visual() {
+		for _, a := range l.selectedArticles() {
+			if l.selection.marked == nil {
+				l.selection.marked = make(map[string]bool)
+			}
+			l.selection.marked[a.ID] = true
+		}
+		l.selection.anchor = ""
+		return
+	}
+
+	if a, ok := l.selected(); ok {
+		l.selection.anchor = a.ID
+	}
+}
+
+func (l *articleList) clearSelection() bool {
+	if len(l.selection.marked) == 0 && !l.visual() {
+		return false
+	}
+	l.selection = selection{}
+	return true
+}
+
+// visualRange returns bounds of the range selected in visual mode.
+func (l *articleList) visualRange() (from, to int, ok bool) {
+	if !l.visual() {
+		return 0, 0, false
+	}
+	for i, a := range l.articles {
+		if a.ID == l.selection.anchor {
+			return min(i, l.cursor), max(i, l.cursor), true
+		}
+	}
+	return 0, 0, false
+}
+
+func (l *articleList) isMarked(i int) bool {
+	if l.selection.marked[l.articles[i].ID] {
+		return true
+	}
+	from, to, ok := l.visualRange()
+	return ok && i >= from && i <= to
+}
+
+// selectedArticles returns marked articles, in order of the list.
+func (l *articleList) selectedArticles() []store.Article {
+	var res []store.Article
+	for i, a := range l.articles {
+		if l.isMarked(i) {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+func (l *articleList) selectionView() string {
+	n := len(l.selectedArticles())
+	switch {
+	case l.visual():
+		return fmt.Sprintf("-- visual -- %d selected", n)
+	case n > 0:
+		return fmt.Sprintf("%d selected", n)
+	default:
+		return ""
+	}
+}
+
+// targetArticles returns articles that actions of the focused pane apply to:
+// the selection, or the article under the cursor.
+func (m *Model) targetArticles() []store.Article {
+	switch m.focus {
+	case articlesPane:
+		if s := m.articles.selectedArticles(); len(s) > 0 {
+			return s
+		}
+		if a, ok := m.articles.selected(); ok {
+			return []store.Article{a}
+		}
+	case readerPane:
+		if m.reader.article != nil {
+			return []store.Article{*m.reader.article}
+		}
+	}
+	return nil
+}
+
+// bulkStatus applies the action to the target articles, and clears the selection.
+func (m *Model) bulkStatus(apply func(...store.Article) tea.Cmd) tea.Cmd {
+	articles := m.targetArticles()
+	if len(articles) == 0 {
+		return nil
+	}
+	m.articles.clearSelection()
+	return apply(articles...)
+}
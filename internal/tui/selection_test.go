@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"olexsmir.xyz/smutok/internal/store"
+	"olexsmir.xyz/x/is"
+)
+
+func selectedIDs(l *articleList) string {
+	var ids []string
+	for _, a := range l.selectedArticles() {
+		ids = append(ids, a.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestSelection(t *testing.T) {
+	l := &articleList{articles: []store.Article{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}}}
+
+	t.Run("marks", func(t *testing.T) {
+		l.toggleMark()
+		l.move(2)
+		l.toggleMark()
+		is.Equal(t, selectedIDs(l), "1,3")
+
+		l.toggleMark()
+		is.Equal(t, selectedIDs(l), "1")
+	})
+
+	t.Run("visual range", func(t *testing.T) {
+		l.toggleVisual()
+		l.move(1)
+		is.Equal(t, selectedIDs(l), "1,3,4")
+
+		l.move(-3)
+		is.Equal(t, selectedIDs(l), "1,2,3")
+
+		l.toggleVisual()
+		l.move(4)
+		is.Equal(t, l.visual(), false)
+		is.Equal(t, selectedIDs(l), "1,2,3")
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		is.Equal(t, l.clearSelection(), true)
+		is.Equal(t, selectedIDs(l), "")
+		is.Equal(t, l.clearSelection(), false)
+	})
+}
+
+func TestSelectionKeys(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.press("enter", "v", "j", "s")
+	is.Equal(t, a.article("1").IsStarred, true)
+	is.Equal(t, a.article("2").IsStarred, true)
+	is.Equal(t, a.article("3").IsStarred, false)
+
+	is.Equal(t, selectedIDs(&a.m.articles), "")
+
+	a.press("space", "space")
+	is.Equal(t, selectedIDs(&a.m.articles), "2,3")
+	a.press("esc")
+	is.Equal(t, selectedIDs(&a.m.articles), "")
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"errors"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+type sessionLoadedMsg struct{ session *store.Session }
+
+func (m *Model) loadSession() tea.Cmd {
+	ctx, db := m.ctx, m.store
+	return func() tea.Msg {
+		ses, err := db.GetSession(ctx)
+		if errors.Is(err, store.ErrNotFound) {
+			return sessionLoadedMsg{}
+		}
+		if err != nil {
+			return errMsg{err}
+		}
+		return sessionLoadedMsg{&ses}
+	}
+}
+
+// startRestore applies filters of the previous session, the node and article
+// are restored once they're loaded.
+func (m *Model) startRestore(msg sessionLoadedMsg) {
+	ses := msg.session
+	if ses == nil {
+		return
+	}
+
+	m.restore = ses
+	m.articles.query = ses.Query
+	m.articles.author = ses.Author
+	m.articles.oldest = ses.OldestFirst
+}
+
+// restoreArticle selects the article of the previous session in the list,
+// and opens it in the reader, if it was open.
+// Pages are loaded until the article is found, or the list ends.
+func (m *Model) restoreArticle() tea.Cmd {
+	ses := m.restore
+	if !m.articles.selectID(ses.ArticleID) {
+		if next := m.articles.loadNext(m.ctx, m.store); next != nil {
+			return next
+		}
+		m.restore = nil
+		return nil
+	}
+
+	m.restore = nil
+	if !ses.ReaderOpen {
+		return nil
+	}
+
+	m.focus = readerPane
+	m.restoreScroll = ses.Scroll
+	return loadArticle(m.ctx, m.store, ses.ArticleID)
+}
+
+// saveSession is called on quit, so errors are only logged.
+func (m *Model) saveSession() {
+	ses := store.Session{
+		Query:       m.articles.query,
+		Author:      m.articles.author,
+		OldestFirst: m.articles.oldest,
+	}
+	if n, ok := m.sidebar.selected(); ok {
+		ses.NodeID = n.id
+	}
+	if a, ok := m.articles.selected(); ok {
+		ses.ArticleID = a.ID
+	}
+	if a := m.reader.article; a != nil {
+		ses.ArticleID = a.ID
+		ses.ReaderOpen = true
+		ses.Scroll = m.reader.viewport.YOffset
+	}
+
+	if err := m.store.SaveSession(m.ctx, ses); err != nil {
+		slog.Error("failed to save session", "err", err)
+	}
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestRestoreSession(t *testing.T) {
+	t.Run("reader", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("enter", "j", "enter", "q")
+		is.Equal(t, a.quit, true)
+
+		a = startTestApp(t, nil, a.db)
+		is.Equal(t, a.m.focus, readerPane)
+		is.Equal(t, a.m.reader.article.ID, "2")
+		is.Equal(t, a.m.articles.cursor, 1)
+	})
+
+	t.Run("article on a later page", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		ctx := context.Background()
+		for i := range articlesPageSize {
+			id := fmt.Sprintf("old%d", i)
+			is.Err(t, a.db.UpsertArticle(ctx, id, "feed/1", id, "", "", "", 1000-i), nil)
+		}
+
+		a.run(loadSidebar(ctx, a.db))
+		a.press("enter", "G", "G", "enter", "q")
+		is.Equal(t, a.m.reader.article.ID, "old99")
+
+		a = startTestApp(t, nil, a.db)
+		is.Equal(t, a.m.focus, readerPane)
+		is.Equal(t, a.m.reader.article.ID, "old99")
+		is.Equal(t, len(a.m.articles.articles), articlesPageSize+3)
+	})
+}
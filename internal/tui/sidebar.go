@@ -0,0 +1,271 @@
+package tui
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"olexsmir.xyz/smutok/internal/render"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+type sidebarNodeKind int
+
+const (
+	viewNode sidebarNodeKind = iota
+	folderNode
+	feedNode
+)
+
+type sidebarNode struct {
+	kind   sidebarNodeKind
+	id     string
+	title  string
+	depth  int
+	count  int
+	filter store.ArticlesFilter
+
+	// stream is the id of the node on the server, empty if there's none
+	stream string
+}
+
+type sidebar struct {
+	views     []smartView
+	folders   []store.Folder
+	feeds     []store.Feed
+	collapsed map[string]bool
+
+	nodes  []sidebarNode
+	cursor int
+	offset int
+
+	// pendingID is selected once the sidebar is loaded again
+	pendingID string
+}
+
+func newSidebar() sidebar {
+	return sidebar{collapsed: make(map[string]bool)}
+}
+
+type sidebarLoadedMsg struct {
+	views   []smartView
+	folders []store.Folder
+	feeds   []store.Feed
+}
+
+func loadSidebar(ctx context.Context, db *store.Sqlite) tea.Cmd {
+	return func() tea.Msg {
+		views, err := loadSmartViews(ctx, db)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		folders, err := db.GetFolders(ctx)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		feeds, err := db.GetFeeds(ctx)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		// names come from the server, and are shown as they are
+		for i := range folders {
+			folders[i].Name = render.Sanitize(folders[i].Name)
+		}
+		for i := range feeds {
+			feeds[i].Title = render.Sanitize(feeds[i].Title)
+			feeds[i].URL = render.Sanitize(feeds[i].URL)
+		}
+
+		return sidebarLoadedMsg{views: views, folders: folders, feeds: feeds}
+	}
+}
+
+func (s *sidebar) setData(msg sidebarLoadedMsg) {
+	s.views = msg.views
+	s.folders = msg.folders
+	s.feeds = msg.feeds
+	s.rebuild()
+	if s.pendingID != "" {
+		s.selectID(s.pendingID)
+		s.pendingID = ""
+	}
+}
+
+// rebuild flattens views, folders and feeds into the list of visible nodes,
+// feeds that are not in any folder are placed after the folders.
+func (s *sidebar) rebuild() {
+	var selectedID string
+	if n, ok := s.selected(); ok {
+		selectedID = n.id
+	}
+
+	feedsByID := make(map[string]store.Feed, len(s.feeds))
+	for _, f := range s.feeds {
+		feedsByID[f.ID] = f
+	}
+
+	s.nodes = s.nodes[:0]
+	for _, v := range s.views {
+		s.nodes = append(s.nodes, sidebarNode{
+			kind:   viewNode,
+			id:     v.id,
+			title:  v.title,
+			count:  v.count,
+			filter: v.filter,
+			stream: v.stream,
+		})
+	}
+
+	inFolder := make(map[string]bool)
+	for _, folder := range s.folders {
+		s.nodes = append(s.nodes, sidebarNode{
+			kind:   folderNode,
+			id:     folder.ID,
+			title:  folder.Name,
+			count:  folder.UnreadCount,
+			filter: store.ArticlesFilter{FolderID: folder.ID},
+			stream: folder.ID,
+		})
+
+		for _, feedID := range folder.FeedIDs {
+			inFolder[feedID] = true
+			feed, ok := feedsByID[feedID]
+			if !ok || s.collapsed[folder.ID] {
+				continue
+			}
+
+			s.nodes = append(s.nodes, feedSidebarNode(feed, 1))
+		}
+	}
+
+	for _, feed := range s.feeds {
+		if inFolder[feed.ID] {
+			continue
+		}
+		s.nodes = append(s.nodes, feedSidebarNode(feed, 0))
+	}
+
+	s.cursor = 0
+	for i, n := range s.nodes {
+		if n.id == selectedID {
+			s.cursor = i
+			break
+		}
+	}
+}
+
+func feedSidebarNode(feed store.Feed, depth int) sidebarNode {
+	return sidebarNode{
+		kind:   feedNode,
+		id:     feed.ID,
+		title:  feed.Title,
+		depth:  depth,
+		count:  feed.UnreadCount,
+		filter: store.ArticlesFilter{FeedID: feed.ID},
+		stream: feed.ID,
+	}
+}
+
+// updateCounts adjusts counters of views, the feed and folders it's in,
+// after the article status has changed.
+func (s *sidebar) updateCounts(from, to store.Article) {
+	for i := range s.views {
+		s.views[i].count += countDelta(s.views[i].countFilter, from, to)
+	}
+
+	if delta := unreadDelta(from, to); delta != 0 {
+		for i := range s.feeds {
+			if s.feeds[i].ID == to.FeedID {
+				s.feeds[i].UnreadCount += delta
+			}
+		}
+		for i := range s.folders {
+			if slices.Contains(s.folders[i].FeedIDs, to.FeedID) || slices.Contains(to.Labels, s.folders[i].ID) {
+				s.folders[i].UnreadCount += delta
+			}
+		}
+	}
+
+	s.rebuild()
+}
+
+func (s *sidebar) selected() (sidebarNode, bool) {
+	if s.cursor < 0 || s.cursor >= len(s.nodes) {
+		return sidebarNode{}, false
+	}
+	return s.nodes[s.cursor], true
+}
+
+// selectID moves the cursor to the node, if it's visible.
+func (s *sidebar) selectID(id string) bool {
+	for i, n := range s.nodes {
+		if n.id == id {
+			s.cursor = i
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sidebar) move(delta int) bool {
+	prev := s.cursor
+	s.cursor = clamp(s.cursor+delta, 0, len(s.nodes)-1)
+	return prev != s.cursor
+}
+
+func (s *sidebar) toggleFolder() {
+	n, ok := s.selected()
+	if !ok || n.kind != folderNode {
+		return
+	}
+	s.collapsed[n.id] = !s.collapsed[n.id]
+	s.rebuild()
+}
+
+func (s *sidebar) view(width, height int, focused bool) string {
+	if len(s.nodes) == 0 {
+		return mutedStyle.Render(truncate("no feeds, run sync", width))
+	}
+
+	s.offset = scrollOffset(s.offset, s.cursor, height, len(s.nodes))
+
+	var b strings.Builder
+	for i := s.offset; i < min(s.offset+height, len(s.nodes)); i++ {
+		n := s.nodes[i]
+
+		style := lipgloss.NewStyle()
+		text := strings.Repeat("  ", n.depth) + n.title
+		if n.kind == folderNode {
+			style = folderStyle
+			text = "▾ " + n.title
+			if s.collapsed[n.id] {
+				text = "▸ " + n.title
+			}
+		}
+		if i == s.cursor {
+			style = selectionStyle(focused)
+		}
+
+		var count string
+		if n.count > 0 {
+			count = " " + strconv.Itoa(n.count)
+		}
+
+		text = truncate(text, max(width-len(count), 0))
+		gap := strings.Repeat(" ", max(width-lipgloss.Width(text)-len(count), 0))
+		line := style.Width(width).Render(text + gap + count)
+
+		if i > s.offset {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,23 @@
+package tui
+
+import (
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestSmartViews(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.press("enter", "j", "s", "h")
+
+	views := map[string]string{}
+	for range len(a.m.sidebar.views) {
+		n, _ := a.m.sidebar.selected()
+		views[n.title] = a.listed()
+		a.press("j")
+	}
+	is.Equal(t, views["Unread"], "1 2 3 ")
+	is.Equal(t, views["Starred"], "2 ")
+	is.Equal(t, views["Today"], "")
+	is.Equal(t, views["All articles"], "1 2 3 ")
+}
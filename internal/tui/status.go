@@ -0,0 +1,284 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+// writes saves changes of articles one at a time, in the order they were made,
+// the views are updated right away, so changes can be made faster than they're saved.
+type writes struct {
+	busy    bool
+	pending []queuedWrite
+
+	// seq numbers the changes, latest is number of the last change
+	// of each status (or label) of an article, see [changeKey]
+	seq    int
+	latest map[string]int
+
+	// quit is set when the tui quits once the writes are done
+	quit bool
+}
+
+type queuedWrite struct {
+	write tea.Cmd
+	then  []tea.Cmd
+}
+
+type writeDoneMsg struct {
+	msg  tea.Msg
+	then []tea.Cmd
+}
+
+// queueWrite runs the write after the previous ones are done,
+// then cmds are run after it.
+func (m *Model) queueWrite(write tea.Cmd, then ...tea.Cmd) tea.Cmd {
+	w := queuedWrite{write: write, then: then}
+	if m.writes.busy {
+		m.writes.pending = append(m.writes.pending, w)
+		return nil
+	}
+	m.writes.busy = true
+	return w.run
+}
+
+func (w queuedWrite) run() tea.Msg {
+	return writeDoneMsg{msg: w.write(), then: w.then}
+}
+
+// finishWrite handles result of the finished write, and starts the next one.
+func (m *Model) finishWrite(msg writeDoneMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	if msg.msg != nil {
+		_, cmd := m.Update(msg.msg)
+		cmds = append(cmds, cmd)
+	}
+	cmds = append(cmds, m.nextWrite())
+	return tea.Batch(append(cmds, msg.then...)...)
+}
+
+func (m *Model) nextWrite() tea.Cmd {
+	if len(m.writes.pending) == 0 {
+		// nothing can be rolled back anymore
+		m.writes.busy = false
+		m.writes.latest = nil
+		if m.writes.quit {
+			m.writes.quit = false
+			return m.quit()
+		}
+		return nil
+	}
+
+	w := m.writes.pending[0]
+	m.writes.pending = m.writes.pending[1:]
+	if m.writes.quit {
+		m.flushing = len(m.writes.pending) + 1
+	}
+	return w.run
+}
+
+// track records a change of the articles, and returns its number.
+func (w *writes) track(articles []store.Article, what string) int {
+	if w.latest == nil {
+		w.latest = make(map[string]int)
+	}
+
+	w.seq++
+	for _, a := range articles {
+		w.latest[changeKey(a.ID, what)] = w.seq
+	}
+	return w.seq
+}
+
+// isLatest reports whether the change is the last one of the article.
+func (w *writes) isLatest(seq int, articleID, what string) bool {
+	return w.latest[changeKey(articleID, what)] == seq
+}
+
+// changeKey identifies what is changed in the article, its status or a label.
+func changeKey(articleID, what string) string { return articleID + "\n" + what }
+
+// statusKind is what the action changes, read and unread change the same status.
+func statusKind(action store.Action) string {
+	switch action {
+	case store.Read, store.Unread:
+		return "read"
+	default:
+		return "star"
+	}
+}
+
+type statusChangeFailedMsg struct {
+	// articles are the state before the change
+	articles []store.Article
+	action   store.Action
+	seq      int
+	err      error
+}
+
+func changeStatus(ctx context.Context, db *store.Sqlite, articles []store.Article, action store.Action, seq int) tea.Cmd {
+	return func() tea.Msg {
+		ids := make([]string, len(articles))
+		for i, a := range articles {
+			ids[i] = a.ID
+		}
+
+		if err := db.ChangeArticlesStatus(ctx, ids, action); err != nil {
+			return statusChangeFailedMsg{articles: articles, action: action, seq: seq, err: err}
+		}
+		return nil
+	}
+}
+
+// applyAction returns copy of the article with applied action.
+func applyAction(a store.Article, action store.Action) store.Article {
+	switch action {
+	case store.Read:
+		a.IsRead = true
+	case store.Unread:
+		a.IsRead = false
+	case store.Star:
+		a.IsStarred = true
+	case store.Unstar:
+		a.IsStarred = false
+	}
+	return a
+}
+
+func unreadDelta(from, to store.Article) int {
+	switch {
+	case from.IsRead && !to.IsRead:
+		return 1
+	case !from.IsRead && to.IsRead:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// setStatus updates the articles in the views right away, and saves the change
+// to the store, if that fails the change is rolled back.
+// Articles that already have the status are skipped.
+func (m *Model) setStatus(action store.Action, articles ...store.Article) tea.Cmd {
+	var changed []store.Article
+	for _, a := range articles {
+		if to := applyAction(a, action); to.IsRead != a.IsRead || to.IsStarred != a.IsStarred {
+			m.replaceArticle(a, to)
+			changed = append(changed, a)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	seq := m.writes.track(changed, statusKind(action))
+	return m.queueWrite(
+		changeStatus(m.ctx, m.store, changed, action, seq),
+		loadStatus(m.ctx, m.store, m.worker),
+	)
+}
+
+// toggleRead marks the articles as read, if any of them is unread,
+// otherwise marks them as unread.
+func (m *Model) toggleRead(articles ...store.Article) tea.Cmd {
+	for _, a := range articles {
+		if !a.IsRead {
+			return m.setStatus(store.Read, articles...)
+		}
+	}
+	return m.setStatus(store.Unread, articles...)
+}
+
+// toggleStar stars the articles, if any of them isn't starred,
+// otherwise unstars them.
+func (m *Model) toggleStar(articles ...store.Article) tea.Cmd {
+	for _, a := range articles {
+		if !a.IsStarred {
+			return m.setStatus(store.Star, articles...)
+		}
+	}
+	return m.setStatus(store.Unstar, articles...)
+}
+
+// rollbackStatus restores the status of the articles, unless it was changed again.
+func (m *Model) rollbackStatus(msg statusChangeFailedMsg) {
+	kind := statusKind(msg.action)
+	for _, a := range msg.articles {
+		if !m.writes.isLatest(msg.seq, a.ID, kind) {
+			continue
+		}
+
+		// only the changed status is restored, the other one might've changed since
+		from := m.shownArticle(applyAction(a, msg.action))
+		to := from
+		if kind == "read" {
+			to.IsRead = a.IsRead
+		} else {
+			to.IsStarred = a.IsStarred
+		}
+		m.replaceArticle(from, to)
+	}
+	m.handleErr(fmt.Errorf("failed to mark as %s: %w", msg.action, msg.err))
+}
+
+// shownArticle returns the article as it's shown in the list or reader.
+func (m *Model) shownArticle(a store.Article) store.Article {
+	if shown, ok := m.articles.find(a.ID); ok {
+		return shown
+	}
+	if m.reader.article != nil && m.reader.article.ID == a.ID {
+		return *m.reader.article
+	}
+	return a
+}
+
+// replaceArticle replaces article in the list and reader, and updates counters.
+func (m *Model) replaceArticle(from, to store.Article) {
+	m.articles.replace(to)
+	m.reader.replace(to)
+	m.sidebar.updateCounts(from, to)
+}
+
+type statusUndoneMsg struct{ change store.StatusChange }
+
+// undoStatus reverts the last status change, after changes before it are saved.
+func (m *Model) undoStatus() tea.Cmd {
+	ctx, worker := m.ctx, m.worker
+	return m.queueWrite(func() tea.Msg {
+		change, err := worker.Undo(ctx)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return errMsg{fmt.Errorf("failed to undo: %w", err)}
+		}
+		return statusUndoneMsg{change}
+	})
+}
+
+// finishUndo updates the views with statuses restored by undo.
+func (m *Model) finishUndo(msg statusUndoneMsg) tea.Cmd {
+	if len(msg.change.Articles) == 0 {
+		return m.showToast("nothing to undo")
+	}
+
+	for _, s := range msg.change.Articles {
+		restore := func(a store.Article) store.Article {
+			a.IsRead, a.IsStarred, a.Labels = s.IsRead, s.IsStarred, s.Labels
+			return a
+		}
+		if a, ok := m.articles.find(s.ID); ok {
+			m.articles.replace(restore(a))
+		}
+		if m.reader.article != nil && m.reader.article.ID == s.ID {
+			m.reader.replace(restore(*m.reader.article))
+		}
+	}
+
+	return tea.Batch(
+		m.showToast(fmt.Sprintf("undid %s of %d articles", msg.change.Action, len(msg.change.Articles))),
+		loadSidebar(m.ctx, m.store),
+		loadStatus(m.ctx, m.store, m.worker),
+	)
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"context"
+	"testing"
+
+	"olexsmir.xyz/x/is"
+)
+
+func TestSetStatus(t *testing.T) {
+	t.Run("bulk", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("enter", "space", "space", "r")
+		is.Equal(t, a.listed(), "r1 r2 3 ")
+		is.Equal(t, a.article("1").IsRead, true)
+		is.Equal(t, a.article("2").IsRead, true)
+		is.Equal(t, a.article("3").IsRead, false)
+	})
+
+	t.Run("rolls back failed change", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		is.Err(t, a.db.DeleteFeed(context.Background(), "feed/1"), nil)
+
+		a.press("enter", "space", "space", "space", "r")
+		is.Equal(t, a.listed(), "1 2 3 ")
+		is.Equal(t, a.m.err != nil, true)
+		is.Equal(t, a.article("3").IsRead, false)
+	})
+
+	t.Run("saves changes in order", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("enter")
+
+		_, first := a.m.Update(testKey("r"))
+		_, second := a.m.Update(testKey("r"))
+		is.Equal(t, second == nil, true)
+		is.Equal(t, a.listed(), "1 2 3 ")
+
+		a.run(first)
+		is.Equal(t, a.article("1").IsRead, false)
+		is.Equal(t, a.m.writes.busy, false)
+	})
+
+	t.Run("rollback keeps newer changes", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		is.Err(t, a.db.DeleteFeed(context.Background(), "feed/1"), nil)
+		a.press("enter")
+
+		_, read := a.m.Update(testKey("r"))
+		a.m.Update(testKey("s"))
+		a.m.Update(read()) // only the first change is done
+
+		ar, _ := a.m.articles.find("1")
+		is.Equal(t, ar.IsRead, false)
+		is.Equal(t, ar.IsStarred, true)
+	})
+
+	t.Run("undo", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("enter", "r", "j", "s", "u")
+		is.Equal(t, a.article("2").IsStarred, false)
+		is.Equal(t, a.article("1").IsRead, true)
+
+		a.press("u")
+		is.Equal(t, a.listed(), "1 2 3 ")
+		is.Equal(t, a.article("1").IsRead, false)
+	})
+
+	t.Run("undo waits for the change", func(t *testing.T) {
+		a := newTestApp(t, nil)
+		a.press("enter")
+
+		_, read := a.m.Update(testKey("r"))
+		_, undo := a.m.Update(testKey("u"))
+		is.Equal(t, undo == nil, true)
+
+		a.run(read)
+		is.Equal(t, a.listed(), "1 2 3 ")
+		is.Equal(t, a.article("1").IsRead, false)
+	})
+}
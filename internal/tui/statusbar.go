@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+const statusRefreshInterval = 5 * time.Second
+
+type statusBar struct {
+	lastSync int64
+	pending  int
+	offline  bool
+}
+
+type statusLoadedMsg statusBar
+
+func loadStatus(ctx context.Context, db *store.Sqlite, worker Worker) tea.Cmd {
+	return func() tea.Msg {
+		lastSync, err := db.GetLastSyncTime(ctx)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return errMsg{err}
+		}
+
+		pending, err := db.CountPendingActions(ctx)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return statusLoadedMsg{
+			lastSync: lastSync,
+			pending:  pending,
+			offline:  worker.Offline(),
+		}
+	}
+}
+
+type statusTickMsg struct{}
+
+func statusTick() tea.Cmd {
+	return tea.Tick(statusRefreshInterval, func(time.Time) tea.Msg {
+		return statusTickMsg{}
+	})
+}
+
+func (s statusBar) view() string {
+	var parts []string
+	if s.offline {
+		parts = append(parts, errStyle.Render("offline"))
+	}
+	if s.pending > 0 {
+		parts = append(parts, mutedStyle.Render(fmt.Sprintf("%d queued", s.pending)))
+	}
+
+	if s.lastSync == 0 {
+		parts = append(parts, mutedStyle.Render("never synced"))
+	} else {
+		parts = append(parts, mutedStyle.Render("synced "+formatAgo(time.Unix(s.lastSync, 0))))
+	}
+
+	return strings.Join(parts, mutedStyle.Render(" · "))
+}
+
+func formatAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return t.Format("2006-01-02")
+	}
+}
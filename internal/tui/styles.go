@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	accentColor = lipgloss.Color("62")
+	mutedColor  = lipgloss.Color("241")
+
+	paneStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(mutedColor)
+
+	focusedPaneStyle = paneStyle.
+				BorderForeground(accentColor)
+
+	selectedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("230")).
+			Background(accentColor)
+
+	inactiveSelectedStyle = lipgloss.NewStyle().
+				Reverse(true)
+
+	folderStyle = lipgloss.NewStyle().Bold(true)
+	mutedStyle  = lipgloss.NewStyle().Foreground(mutedColor)
+	titleStyle  = lipgloss.NewStyle().Bold(true).Foreground(accentColor)
+	toastStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("222"))
+	errStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Bold(true)
+	markedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("230")).Background(lipgloss.Color("238"))
+	matchStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("222"))
+)
+
+func selectionStyle(focused bool) lipgloss.Style {
+	if focused {
+		return selectedStyle
+	}
+	return inactiveSelectedStyle
+}
+
+func truncate(s string, width int) string {
+	return ansi.Truncate(s, width, "…")
+}
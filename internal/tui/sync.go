@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/freshrss"
+)
+
+type Syncer interface {
+	Sync(ctx context.Context) error
+	Progress() <-chan freshrss.SyncProgress
+}
+
+type (
+	syncProgressMsg freshrss.SyncProgress
+	syncFinishedMsg struct{ err error }
+	autoSyncMsg     struct{ id int }
+)
+
+func runSync(ctx context.Context, syncer Syncer) tea.Cmd {
+	return func() tea.Msg {
+		return syncFinishedMsg{syncer.Sync(ctx)}
+	}
+}
+
+// waitSyncProgress waits for the next progress event of sync.
+func waitSyncProgress(ctx context.Context, syncer Syncer) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case p := <-syncer.Progress():
+			return syncProgressMsg(p)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (m *Model) startSync() tea.Cmd {
+	if m.syncing {
+		return nil
+	}
+
+	m.syncing = true
+	m.syncProgress = freshrss.SyncProgress{}
+	return tea.Batch(runSync(m.ctx, m.syncer), m.listenSyncProgress())
+}
+
+// listenSyncProgress makes sure there's only one progress listener.
+func (m *Model) listenSyncProgress() tea.Cmd {
+	if m.syncListening {
+		return nil
+	}
+	m.syncListening = true
+	return waitSyncProgress(m.ctx, m.syncer)
+}
+
+func (m *Model) updateSyncProgress(msg syncProgressMsg) tea.Cmd {
+	m.syncListening = false
+	m.syncProgress = freshrss.SyncProgress(msg)
+	if msg.Stage == freshrss.SyncDone {
+		return nil
+	}
+	return m.listenSyncProgress()
+}
+
+func (m *Model) finishSync(msg syncFinishedMsg) tea.Cmd {
+	m.syncing = false
+
+	if msg.err != nil {
+		m.handleErr(fmt.Errorf("sync failed: %w", msg.err))
+	}
+	return tea.Batch(
+		loadSidebar(m.ctx, m.store),
+		loadStatus(m.ctx, m.store, m.worker),
+		m.scheduleAutoSync(),
+	)
+}
+
+// scheduleAutoSync schedules the next background sync, if it's enabled,
+// the interval starts over after every sync, manual ones too.
+func (m *Model) scheduleAutoSync() tea.Cmd {
+	if m.cfg.Sync.EveryMinutes <= 0 {
+		return nil
+	}
+
+	m.autoSyncID++
+	id := m.autoSyncID
+	return tea.Tick(time.Duration(m.cfg.Sync.EveryMinutes)*time.Minute, func(time.Time) tea.Msg {
+		return autoSyncMsg{id}
+	})
+}
+
+func (m *Model) autoSync(msg autoSyncMsg) tea.Cmd {
+	// a newer one is scheduled, or a sync is running, it schedules the next one
+	if msg.id != m.autoSyncID || m.syncing {
+		return nil
+	}
+
+	// not worth an error every time, the status bar shows it
+	if m.worker.Offline() {
+		return m.scheduleAutoSync()
+	}
+	return m.startSync()
+}
+
+func (m *Model) syncStatus() string {
+	p := m.syncProgress
+	if p.Steps == 0 {
+		return "syncing…"
+	}
+	return fmt.Sprintf("syncing %s (%d/%d)…", p.Stage, p.Step, p.Steps)
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"context"
+	"testing"
+
+	"olexsmir.xyz/smutok/internal/config"
+	"olexsmir.xyz/smutok/internal/store"
+	"olexsmir.xyz/x/is"
+)
+
+type offlineWorker bool
+
+func (w offlineWorker) Errors() <-chan error        { return nil }
+func (w offlineWorker) Offline() bool               { return bool(w) }
+func (w offlineWorker) Flush(context.Context) error { return nil }
+
+func (w offlineWorker) Undo(context.Context) (store.StatusChange, error) {
+	return store.StatusChange{}, store.ErrNotFound
+}
+
+func TestAutoSync(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		m := &Model{cfg: &config.Config{}}
+		is.Equal(t, m.scheduleAutoSync() == nil, true)
+	})
+
+	t.Run("skips stale and running", func(t *testing.T) {
+		m := &Model{cfg: &config.Config{}, worker: offlineWorker(false)}
+		m.cfg.Sync.EveryMinutes = 5
+
+		m.scheduleAutoSync()
+		m.scheduleAutoSync()
+		is.Equal(t, m.autoSync(autoSyncMsg{id: 1}) == nil, true)
+		is.Equal(t, m.syncing, false)
+
+		m.syncing = true // manual sync
+		is.Equal(t, m.autoSync(autoSyncMsg{id: 2}) == nil, true)
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		m := &Model{cfg: &config.Config{}, worker: offlineWorker(true)}
+		m.cfg.Sync.EveryMinutes = 5
+
+		m.scheduleAutoSync()
+		is.Equal(t, m.autoSync(autoSyncMsg{id: 1}) != nil, true)
+		is.Equal(t, m.syncing, false)
+		is.Equal(t, m.autoSyncID, 2)
+	})
+
+	t.Run("keeps unpushed changes", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.Sync.EveryMinutes = 5
+		a := newTestApp(t, cfg)
+		a.press("enter", "r")
+
+		a.send(autoSyncMsg{id: a.m.autoSyncID})
+		is.Equal(t, a.syncer.synced, 1)
+		is.Equal(t, a.m.autoSyncID, 2)
+		is.Equal(t, a.article("1").IsRead, true)
+		is.Equal(t, a.listed(), "2 3 ") // the unread list is reloaded
+	})
+}
+
+func TestAutoSyncSchedule(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Sync.EveryMinutes = 5
+	a := newTestApp(t, cfg)
+	is.Equal(t, a.m.autoSyncID, 1)
+
+	a.worker.offline = true
+	a.send(autoSyncMsg{id: a.m.autoSyncID})
+	is.Equal(t, a.syncer.synced, 0)
+	is.Equal(t, a.m.autoSyncID, 2)
+
+	a.worker.offline = false
+	a.press("R") // the interval starts over
+	is.Equal(t, a.m.autoSyncID, 3)
+
+	a.send(autoSyncMsg{id: 2})
+	is.Equal(t, a.syncer.synced, 1)
+
+	a.send(autoSyncMsg{id: a.m.autoSyncID})
+	is.Equal(t, a.syncer.synced, 2)
+}
+
+func TestSyncKey(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.press("R")
+	is.Equal(t, a.syncer.synced, 1)
+	is.Equal(t, a.m.syncing, false)
+	is.Equal(t, a.m.err, nil)
+}
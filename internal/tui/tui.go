@@ -2,64 +2,653 @@ package tui
 
 import (
 	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+	"olexsmir.xyz/smutok/internal/config"
+	"olexsmir.xyz/smutok/internal/freshrss"
+	"olexsmir.xyz/smutok/internal/render"
 	"olexsmir.xyz/smutok/internal/store"
 )
 
-type Syncer interface {
-	Sync(ctx context.Context) error
+type Worker interface {
+	Errors() <-chan error
+	Offline() bool
+	Flush(ctx context.Context) error
+	Undo(ctx context.Context) (store.StatusChange, error)
 }
 
+type pane int
+
+const (
+	sidebarPane pane = iota
+	articlesPane
+	readerPane
+)
+
+func (p pane) String() string {
+	switch p {
+	case sidebarPane:
+		return "sidebar"
+	case articlesPane:
+		return "articles"
+	case readerPane:
+		return "reader"
+	default:
+		return "unknown"
+	}
+}
+
+type overlay int
+
+const (
+	noOverlay overlay = iota
+	helpOverlay
+	errHistoryOverlay
+	linksOverlay
+	searchOverlay
+	feedsOverlay
+)
+
 type Model struct {
 	ctx context.Context
 
-	isQutting bool
-	showErr   bool
-	err       error
+	isQutting  bool
+	showErr    bool
+	err        error
+	errHistory []errEntry
+
+	width  int
+	height int
+	focus  pane
+
+	// pane widths set by the user, zero means automatic
+	sidebarWidth  int
+	articlesWidth int
+
+	overlay  overlay
+	keys     keyMap
+	commands []command
+
+	toast   string
+	toastID int
+	status  statusBar
+	links   linkPicker
+	search  searchResults
+	feeds   feedManager
+	opener  opener
+
+	// clipboard is the terminal the program renders to,
+	// it's nil if the output isn't a terminal
+	clipboard io.Writer
+
+	readTimerID int
+
+	// restore is the previous session, until it's restored
+	restore       *store.Session
+	restoreScroll int
+
+	catchUp *catchUpPrompt
+	prompt  *prompt
+
+	// flushing is number of actions that are pushed before quitting
+	flushing int
+	// warning is shown after the tui is closed
+	warning string
 
+	syncing       bool
+	syncListening bool
+	syncProgress  freshrss.SyncProgress
+
+	// autoSyncID identifies the scheduled background sync, older ones are ignored
+	autoSyncID int
+
+	writes writes
+
+	sidebar  sidebar
+	articles articleList
+	reader   reader
+
+	cfg    *config.Config
 	syncer Syncer
+	worker Worker
+	editor Editor
 	store  *store.Sqlite
 }
 
 func NewModel(
 	ctx context.Context,
+	cfg *config.Config,
 	syncer Syncer,
+	worker Worker,
+	editor Editor,
 	store *store.Sqlite,
-) *Model {
-	return &Model{
-		ctx:    ctx,
-		syncer: syncer,
-		store:  store,
+) (*Model, error) {
+	keys, err := newKeyMap(cfg.Keys)
+	if err != nil {
+		return nil, err
 	}
+
+	// the program renders to stdout
+	var clipboard io.Writer
+	if term.IsTerminal(os.Stdout.Fd()) {
+		clipboard = os.Stdout
+	}
+
+	return &Model{
+		ctx:       ctx,
+		clipboard: clipboard,
+		cfg:       cfg,
+		syncer:    syncer,
+		worker:    worker,
+		editor:    editor,
+		store:     store,
+		keys:      keys,
+		commands:  defaultCommands(),
+		opener:    newOpener(cfg),
+		sidebar:   newSidebar(),
+		reader:    newReader(),
+	}, nil
 }
 
 func (m *Model) Init() tea.Cmd {
-	return nil
+	cmds := []tea.Cmd{
+		tea.Sequence(m.loadSession(), loadSidebar(m.ctx, m.store)),
+		loadStatus(m.ctx, m.store, m.worker),
+		statusTick(),
+		waitWorkerErr(m.ctx, m.worker),
+	}
+	if m.cfg.Sync.OnStartup {
+		cmds = append(cmds, m.startSync())
+	} else {
+		cmds = append(cmds, m.scheduleAutoSync())
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case errMsg:
-		m.err = msg
-		m.showErr = true
+		m.handleErr(msg.err)
+		return m, nil
+
+	case workerErrMsg:
+		m.handleErr(msg.err)
+		return m, waitWorkerErr(m.ctx, m.worker)
+
+	case sessionLoadedMsg:
+		m.startRestore(msg)
+		return m, nil
+
+	case sidebarLoadedMsg:
+		m.sidebar.setData(msg)
+		if m.restore != nil {
+			m.sidebar.selectID(m.restore.NodeID)
+		}
+		return m, m.loadSelectedNode()
+
+	case articlesLoadedMsg:
+		if m.articles.isStale(msg) {
+			return m, nil // selection has changed while loading
+		}
+		if msg.err != nil {
+			// the list is kept as it is, the page is requested again on the next move
+			m.articles.loading = false
+			m.restore = nil
+			m.handleErr(msg.err)
+			return m, nil
+		}
+		m.articles.setPage(msg)
+		if m.restore != nil {
+			return m, m.restoreArticle()
+		}
+		return m, nil
+
+	case articleLoadedMsg:
+		// the list has the latest statuses, the change might not be saved yet
+		if a, ok := m.articles.find(msg.article.ID); ok {
+			msg.article.IsRead = a.IsRead
+			msg.article.IsStarred = a.IsStarred
+			msg.article.Labels = a.Labels
+		}
+		m.reader.setArticle(msg.article)
+		if m.restoreScroll > 0 {
+			m.reader.viewport.SetYOffset(m.restoreScroll)
+			m.restoreScroll = 0
+		}
+		return m, m.readOnOpen(msg.article)
+
+	case readTimerMsg:
+		return m, m.readTimerExpired(msg)
+
+	case searchResultsMsg:
+		m.showSearchResults(msg)
+		return m, nil
+
+	case markedAllReadMsg:
+		return m, m.finishCatchUp(msg)
+
+	case feedsChangedMsg:
+		return m, m.finishFeedsChange(msg)
+
+	case writeDoneMsg:
+		return m, m.finishWrite(msg)
+
+	case statusChangeFailedMsg:
+		m.rollbackStatus(msg)
+		return m, nil
+
+	case labelChangeFailedMsg:
+		m.rollbackLabel(msg)
+		return m, nil
+
+	case statusUndoneMsg:
+		return m, m.finishUndo(msg)
+
+	case syncProgressMsg:
+		return m, m.updateSyncProgress(msg)
+
+	case syncFinishedMsg:
+		return m, m.finishSync(msg)
+
+	case autoSyncMsg:
+		return m, m.autoSync(msg)
+
+	case flushedMsg:
+		return m, m.finishFlush(msg)
+
+	case statusLoadedMsg:
+		m.status = statusBar(msg)
+		return m, nil
+
+	case statusTickMsg:
+		return m, tea.Batch(loadStatus(m.ctx, m.store, m.worker), statusTick())
+
+	case toastExpiredMsg:
+		if msg.id == m.toastID {
+			m.toast = ""
+		}
 		return m, nil
 
 	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.layout()
+		return m, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q":
-			m.isQutting = true
-			return m, tea.Quit
+		if m.flushing > 0 {
+			return m, m.updateFlush(msg)
+		}
+		if m.catchUp != nil {
+			return m, m.updateCatchUp(msg)
+		}
+		if m.prompt != nil {
+			return m, m.updatePrompt(msg)
+		}
+		if m.overlay != noOverlay {
+			return m, m.updateOverlay(msg)
+		}
+
+		for _, act := range m.keys.actions(msg.String(), m.focus) {
+			if cmd, ok := m.handleAction(act); ok {
+				return m, cmd
+			}
+		}
+
+		if m.focus == readerPane {
+			var cmd tea.Cmd
+			m.reader.viewport, cmd = m.reader.viewport.Update(msg)
+			return m, cmd
 		}
 	}
 	return m, nil
 }
 
+// handleAction performs the action in the focused pane,
+// reports false if the action is not applicable right now.
+func (m *Model) handleAction(act action) (tea.Cmd, bool) {
+	switch act {
+	case actDismiss:
+		if m.showErr {
+			m.showErr = false
+			return nil, true
+		}
+		return nil, m.articles.clearSelection()
+	case actQuit:
+		return m.quit(), true
+	case actHelp:
+		m.overlay = helpOverlay
+		return nil, true
+	case actErrHistory:
+		m.overlay = errHistoryOverlay
+		m.showErr = false
+		return nil, true
+	case actNextPane:
+		m.focus = (m.focus + 1) % 3
+		return nil, true
+	case actPrevPane:
+		m.focus = (m.focus + 2) % 3
+		return nil, true
+	case actSync:
+		return m.startSync(), true
+	case actGrowPane:
+		return nil, m.resizePane(resizeStep)
+	case actShrinkPane:
+		return nil, m.resizePane(-resizeStep)
+	case actResetPanes:
+		m.sidebarWidth, m.articlesWidth = 0, 0
+		m.layout()
+		return nil, true
+	case actMarkAllRead:
+		m.startCatchUp()
+		return nil, true
+	case actFullSearch:
+		m.openPrompt(fullSearchPrompt)
+		return nil, true
+	case actCommand:
+		m.openPrompt(commandPrompt)
+		return nil, true
+	case actUndo:
+		return m.undoStatus(), true
+	case actManageFeeds:
+		m.openFeedManager()
+		return nil, true
+	case actLabel:
+		m.openCommand("label ")
+		return nil, true
+	}
+
+	switch m.focus {
+	case sidebarPane:
+		return m.sidebarAction(act), true
+	case articlesPane:
+		return m.articlesAction(act), true
+	case readerPane:
+		return m.readerAction(act), true
+	}
+	return nil, false
+}
+
+func (m *Model) updateOverlay(msg tea.KeyMsg) tea.Cmd {
+	switch m.overlay {
+	case linksOverlay:
+		return m.updateLinkPicker(msg)
+	case searchOverlay:
+		return m.updateSearchResults(msg)
+	case feedsOverlay:
+		return m.updateFeedManager(msg)
+	}
+
+	key := msg.String()
+	switch {
+	case key == "esc", m.keys.is(key, actQuit),
+		m.overlay == helpOverlay && m.keys.is(key, actHelp),
+		m.overlay == errHistoryOverlay && m.keys.is(key, actErrHistory):
+		m.overlay = noOverlay
+	}
+	return nil
+}
+
+func (m *Model) sidebarAction(act action) tea.Cmd {
+	var moved bool
+	switch act {
+	case actDown:
+		moved = m.sidebar.move(1)
+	case actUp:
+		moved = m.sidebar.move(-1)
+	case actTop:
+		moved = m.sidebar.move(-len(m.sidebar.nodes))
+	case actBottom:
+		moved = m.sidebar.move(len(m.sidebar.nodes))
+	case actPageDown:
+		moved = m.sidebar.move(m.paneHeight() / 2)
+	case actPageUp:
+		moved = m.sidebar.move(-m.paneHeight() / 2)
+	case actToggleFolder:
+		m.sidebar.toggleFolder()
+	case actOpen:
+		m.focus = articlesPane
+	}
+
+	if moved {
+		return m.loadSelectedNode()
+	}
+	return nil
+}
+
+func (m *Model) articlesAction(act action) tea.Cmd {
+	prev := m.articles.cursor
+	switch act {
+	case actDown:
+		m.articles.move(1)
+		return tea.Batch(m.readPassed(prev), m.articles.loadMore(m.ctx, m.store))
+	case actUp:
+		m.articles.move(-1)
+	case actTop:
+		m.articles.move(-len(m.articles.articles))
+	case actBottom:
+		m.articles.move(len(m.articles.articles))
+		return tea.Batch(m.readPassed(prev), m.articles.loadMore(m.ctx, m.store))
+	case actPageDown:
+		m.articles.move(m.paneHeight() / 2)
+		return tea.Batch(m.readPassed(prev), m.articles.loadMore(m.ctx, m.store))
+	case actPageUp:
+		m.articles.move(-m.paneHeight() / 2)
+	case actOpen:
+		if a, ok := m.articles.selected(); ok {
+			m.focus = readerPane
+			return loadArticle(m.ctx, m.store, a.ID)
+		}
+	case actBack:
+		m.focus = sidebarPane
+	case actToggleRead:
+		return m.bulkStatus(m.toggleRead)
+	case actToggleStar:
+		return m.bulkStatus(m.toggleStar)
+	case actMark:
+		m.articles.toggleMark()
+		m.articles.move(1)
+		return m.articles.loadMore(m.ctx, m.store)
+	case actVisual:
+		m.articles.toggleVisual()
+	case actOpenArticle:
+		if a, ok := m.articles.selected(); ok && a.Href != "" {
+			return m.openURLs(a.Href)
+		}
+	case actYankURL, actYankTitle, actYankMarkdown:
+		if a, ok := m.articles.selected(); ok {
+			return m.yank(a, act)
+		}
+	case actSearch:
+		m.openPrompt(articlesSearchPrompt)
+	case actNextMatch:
+		m.articles.jumpMatch(1)
+	case actPrevMatch:
+		m.articles.jumpMatch(-1)
+	}
+	return nil
+}
+
+func (m *Model) readerAction(act action) tea.Cmd {
+	switch act {
+	case actDown:
+		m.reader.viewport.ScrollDown(1)
+	case actUp:
+		m.reader.viewport.ScrollUp(1)
+	case actTop:
+		m.reader.viewport.GotoTop()
+	case actBottom:
+		m.reader.viewport.GotoBottom()
+	case actPageDown:
+		m.reader.viewport.HalfPageDown()
+	case actPageUp:
+		m.reader.viewport.HalfPageUp()
+	case actBack:
+		m.focus = articlesPane
+	case actToggleRead:
+		if m.reader.article != nil {
+			return m.toggleRead(*m.reader.article)
+		}
+	case actToggleStar:
+		if m.reader.article != nil {
+			return m.toggleStar(*m.reader.article)
+		}
+	case actOpenArticle:
+		if m.reader.article != nil && m.reader.article.Href != "" {
+			return m.openURLs(m.reader.article.Href)
+		}
+	case actLinks:
+		if m.reader.article != nil {
+			m.openLinkPicker()
+		}
+	case actYankURL, actYankTitle, actYankMarkdown:
+		if m.reader.article != nil {
+			return m.yank(*m.reader.article, act)
+		}
+	case actSearch:
+		m.openPrompt(readerSearchPrompt)
+	case actNextMatch:
+		m.reader.jumpMatch(1)
+	case actPrevMatch:
+		m.reader.jumpMatch(-1)
+	}
+	return nil
+}
+
+func (m *Model) loadSelectedNode() tea.Cmd {
+	n, ok := m.sidebar.selected()
+	if !ok {
+		m.articles.setPage(articlesLoadedMsg{})
+		return nil
+	}
+	return m.articles.open(m.ctx, m.store, n)
+}
+
 func (m *Model) View() string {
 	if m.isQutting {
 		return ""
 	}
-	return "are you feeling smutok?"
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+
+	h := m.paneHeight()
+	switch m.overlay {
+	case helpOverlay:
+		return m.overlayView(m.helpView(m.width-2, h), "esc: close")
+	case errHistoryOverlay:
+		return m.overlayView(m.errHistoryView(m.width-2, h), "esc: close")
+	case linksOverlay:
+		return m.overlayView(m.linkPickerView(m.width-2, h), "esc: close")
+	case searchOverlay:
+		return m.overlayView(m.searchResultsView(m.width-2, h), "esc: close")
+	case feedsOverlay:
+		return m.overlayView(m.feedManagerView(m.width-2, h), feedManagerHint)
+	}
+
+	if m.singlePane() {
+		return lipgloss.JoinVertical(lipgloss.Left, m.paneView(m.focus), m.footerView(""))
+	}
+
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.paneView(sidebarPane),
+		m.paneView(articlesPane),
+		m.paneView(readerPane),
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, panes, m.footerView(""))
+}
+
+func (m *Model) paneView(p pane) string {
+	sw, aw, rw := m.paneWidths()
+	h := m.paneHeight()
+
+	switch p {
+	case sidebarPane:
+		return m.paneStyle(p, sw, h).Render(m.sidebar.view(sw, h, m.focus == p))
+	case articlesPane:
+		return m.paneStyle(p, aw, h).Render(m.articles.view(aw, h, m.focus == p))
+	default:
+		return m.paneStyle(p, rw, h).Render(m.reader.view())
+	}
+}
+
+func (m *Model) overlayView(content, hint string) string {
+	return lipgloss.JoinVertical(lipgloss.Left,
+		focusedPaneStyle.Width(m.width-2).Height(m.paneHeight()).MaxHeight(m.paneHeight()+2).Render(content),
+		m.footerView(hint),
+	)
+}
+
+// footerView renders notifications or the hint on the left side,
+// and the status bar on the right.
+func (m *Model) footerView(hint string) string {
+	right := m.status.view()
+	width := max(m.width-lipgloss.Width(right)-1, 0)
+
+	var left string
+	switch {
+	case m.flushing > 0:
+		left = toastStyle.Render(truncate(m.flushStatus(), width))
+	case m.catchUp != nil:
+		left = toastStyle.Render(truncate(m.catchUp.view(), width))
+	case m.prompt != nil:
+		m.prompt.input.Width = max(width-lipgloss.Width(m.prompt.input.Prompt)-1, 1)
+		// the input pads completions past its width
+		left = ansi.Truncate(m.prompt.input.View(), width, "")
+	case m.showErr && m.err != nil:
+		left = m.errBannerView(width)
+	case m.toast != "":
+		left = toastStyle.Render(truncate(render.Sanitize(m.toast), width))
+	case m.syncing:
+		left = mutedStyle.Render(truncate(m.syncStatus(), width))
+	default:
+		left = mutedStyle.Render(truncate(hint, width))
+	}
+
+	gap := strings.Repeat(" ", max(m.width-lipgloss.Width(left)-lipgloss.Width(right), 1))
+	return left + gap + right
+}
+
+const toastTimeout = 5 * time.Second
+
+type toastExpiredMsg struct{ id int }
+
+// showToast shows a message in the footer for a few seconds.
+func (m *Model) showToast(text string) tea.Cmd {
+	m.toastID++
+	m.toast = text
+
+	id := m.toastID
+	return tea.Tick(toastTimeout, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id}
+	})
+}
+
+func (m *Model) paneStyle(p pane, width, height int) lipgloss.Style {
+	style := paneStyle
+	if m.focus == p {
+		style = focusedPaneStyle
+	}
+	return style.Width(width).Height(height).MaxHeight(height + 2)
+}
+
+// scrollOffset returns the first visible row, so the cursor stays in the view.
+func scrollOffset(offset, cursor, height, total int) int {
+	if height <= 0 {
+		return 0
+	}
+	if cursor < offset {
+		offset = cursor
+	}
+	if cursor >= offset+height {
+		offset = cursor - height + 1
+	}
+	return clamp(offset, 0, max(total-height, 0))
 }
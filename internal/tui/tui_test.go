@@ -0,0 +1,291 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"olexsmir.xyz/smutok/internal/config"
+	"olexsmir.xyz/smutok/internal/freshrss"
+	"olexsmir.xyz/smutok/internal/store"
+	"olexsmir.xyz/x/is"
+)
+
+// testApp runs the model the same way the program does, but synchronously.
+type testApp struct {
+	t      *testing.T
+	m      *Model
+	db     *store.Sqlite
+	worker *testWorker
+	syncer *testSyncer
+	quit   bool
+}
+
+// migratedDB is a migrated database, every test starts with a copy of it,
+// since migrating takes most of the time of a test.
+var migratedDB string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "smutok-tui")
+	if err != nil {
+		panic(err)
+	}
+	migratedDB = filepath.Join(dir, "smutok.sqlite")
+	if err := migrate(migratedDB); err != nil {
+		os.RemoveAll(dir)
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func migrate(path string) error {
+	db, err := store.NewSQLite(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Migrate(context.Background())
+}
+
+func newTestStore(t *testing.T) *store.Sqlite {
+	t.Helper()
+
+	data, err := os.ReadFile(migratedDB)
+	is.Err(t, err, nil)
+	path := filepath.Join(t.TempDir(), "smutok.sqlite")
+	is.Err(t, os.WriteFile(path, data, 0o600), nil)
+
+	ctx := context.Background()
+	db, err := store.NewSQLite(path)
+	is.Err(t, err, nil)
+	t.Cleanup(func() { db.Close() })
+
+	is.Err(t, db.UpsertTag(ctx, "user/-/label/Tech"), nil)
+	is.Err(t, db.UpsertSubscription(ctx, "feed/1", "Go Blog", "https://go.dev/feed", "https://go.dev"), nil)
+	is.Err(t, db.UpsertSubscription(ctx, "feed/2", "Lonely", "https://l.com/feed", "https://l.com"), nil)
+	is.Err(t, db.LinkFeedWithFolder(ctx, "feed/1", "user/-/label/Tech"), nil)
+	// newest first, as they're listed
+	for i, a := range []struct{ id, feed, title string }{
+		{"1", "feed/1", "first"},
+		{"2", "feed/1", "second"},
+		{"3", "feed/2", "third"},
+	} {
+		published := 1760000000 - i*60
+		is.Err(t, db.UpsertArticle(ctx, a.id, a.feed, a.title, "<p>"+a.title+"</p>", "", "https://a.com/"+a.id, published), nil)
+	}
+	return db
+}
+
+// newTestApp starts the tui, with unread articles shown in the list.
+func newTestApp(t *testing.T, cfg *config.Config) *testApp {
+	t.Helper()
+	return startTestApp(t, cfg, newTestStore(t))
+}
+
+// startTestApp starts the tui with the store, e.g. to restart it.
+func startTestApp(t *testing.T, cfg *config.Config, db *store.Sqlite) *testApp {
+	t.Helper()
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	worker := &testWorker{db: db, errs: make(chan error)}
+	syncer := &testSyncer{db: db}
+	m, err := NewModel(ctx, cfg, syncer, worker, &testEditor{db: db}, db)
+	is.Err(t, err, nil)
+
+	a := &testApp{t: t, m: m, db: db, worker: worker, syncer: syncer}
+	a.run(m.Init())
+	a.send(tea.WindowSizeMsg{Width: 120, Height: 30})
+	return a
+}
+
+// send passes the message to the model, and runs the returned commands.
+func (a *testApp) send(msg tea.Msg) {
+	a.t.Helper()
+	_, cmd := a.m.Update(msg)
+	a.run(cmd)
+}
+
+// press sends the keys, special ones are named like "enter" or "esc".
+func (a *testApp) press(keys ...string) {
+	a.t.Helper()
+	for _, k := range keys {
+		a.send(testKey(k))
+	}
+}
+
+// typeText sends every rune of s as a key.
+func (a *testApp) typeText(s string) {
+	a.t.Helper()
+	for _, r := range s {
+		a.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// waiting are commands that wait for time to pass or for events,
+// they're dropped, since nothing would ever finish them in tests,
+// tests send the messages they'd return instead, e.g. [toastExpiredMsg].
+var waiting = []string{
+	"bubbletea.Tick.",
+	"tui.waitWorkerErr.",
+	"tui.waitSyncProgress.",
+}
+
+// run runs the command and sends its messages to the model.
+func (a *testApp) run(cmd tea.Cmd) {
+	a.t.Helper()
+	if cmd == nil {
+		return
+	}
+
+	name := runtime.FuncForPC(reflect.ValueOf(cmd).Pointer()).Name()
+	for _, w := range waiting {
+		if strings.Contains(name, w) {
+			return
+		}
+	}
+
+	msg := cmd()
+
+	// batches and sequences
+	if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice && v.Type().Elem() == reflect.TypeFor[tea.Cmd]() {
+		for i := range v.Len() {
+			a.run(v.Index(i).Interface().(tea.Cmd))
+		}
+		return
+	}
+
+	switch msg.(type) {
+	case nil:
+	case tea.QuitMsg:
+		a.quit = true
+	default:
+		a.send(msg)
+	}
+}
+
+func TestToastExpires(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.m.showToast("first")
+	expired := toastExpiredMsg{a.m.toastID}
+	a.m.showToast("second")
+
+	a.send(expired) // of the replaced toast
+	is.Equal(t, a.m.toast, "second")
+
+	a.send(toastExpiredMsg{a.m.toastID})
+	is.Equal(t, a.m.toast, "")
+}
+
+func (a *testApp) article(id string) store.Article {
+	a.t.Helper()
+	res, err := a.db.GetArticle(context.Background(), id)
+	is.Err(a.t, err, nil)
+	return res
+}
+
+// listed returns ids of articles in the list, read ones are prefixed with "r".
+func (a *testApp) listed() string {
+	var res string
+	for _, ar := range a.m.articles.articles {
+		if ar.IsRead {
+			res += "r"
+		}
+		res += ar.ID + " "
+	}
+	return res
+}
+
+func testKey(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	case "space":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+// testSyncer syncs as if all articles are unread on the server.
+type testSyncer struct {
+	db     *store.Sqlite
+	synced int
+}
+
+func (s *testSyncer) Sync(ctx context.Context) error {
+	s.synced++
+	return s.db.SyncReadStatus(ctx, []string{"1", "2", "3"})
+}
+
+func (s *testSyncer) Progress() <-chan freshrss.SyncProgress { return nil }
+
+type testWorker struct {
+	db       *store.Sqlite
+	errs     chan error
+	offline  bool
+	flushErr error
+	flushed  int
+}
+
+func (w *testWorker) Errors() <-chan error { return w.errs }
+func (w *testWorker) Offline() bool        { return w.offline }
+
+func (w *testWorker) Flush(context.Context) error {
+	w.flushed++
+	return w.flushErr
+}
+
+func (w *testWorker) Undo(ctx context.Context) (store.StatusChange, error) {
+	return w.db.UndoStatusChange(ctx)
+}
+
+type testEditor struct{ db *store.Sqlite }
+
+func (e *testEditor) Subscribe(ctx context.Context, u, folder string) (store.Feed, error) {
+	feed := store.Feed{ID: "feed/" + u, Title: u}
+	if err := e.db.UpsertSubscription(ctx, feed.ID, u, u, u); err != nil {
+		return feed, err
+	}
+	return feed, e.Move(ctx, feed.ID, folder)
+}
+
+func (e *testEditor) Unsubscribe(ctx context.Context, id string) error {
+	return e.db.DeleteFeed(ctx, id)
+}
+
+func (e *testEditor) Rename(ctx context.Context, id, title string) error {
+	return e.db.RenameFeed(ctx, id, title)
+}
+
+func (e *testEditor) Move(ctx context.Context, id, folder string) error {
+	if folder == "" {
+		return e.db.SetFeedFolders(ctx, id)
+	}
+	return e.db.SetFeedFolders(ctx, id, freshrss.LabelID(folder))
+}
+
+func (e *testEditor) RenameFolder(ctx context.Context, name, newName string) error {
+	return e.db.RenameFolder(ctx, freshrss.LabelID(name), freshrss.LabelID(newName))
+}
+
+func (e *testEditor) DeleteFolder(ctx context.Context, name string) error {
+	return e.db.DeleteFolder(ctx, freshrss.LabelID(name))
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"olexsmir.xyz/smutok/internal/freshrss"
+	"olexsmir.xyz/smutok/internal/store"
+)
+
+// smartView is a virtual stream of articles shown above the folders.
+type smartView struct {
+	id    string
+	title string
+
+	// filter selects articles that are shown in the view
+	filter store.ArticlesFilter
+
+	// countFilter selects articles that are counted in the sidebar
+	countFilter store.ArticlesFilter
+	count       int
+
+	// stream is the matching stream on the server, if there's one
+	stream string
+}
+
+func smartViews(now time.Time) []smartView {
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, now.Location()).Unix()
+
+	return []smartView{
+		{
+			id:          "view/unread",
+			title:       "Unread",
+			filter:      store.ArticlesFilter{UnreadOnly: true},
+			countFilter: store.ArticlesFilter{UnreadOnly: true},
+			stream:      freshrss.StateReadingList,
+		},
+		{
+			id:          "view/starred",
+			title:       "Starred",
+			filter:      store.ArticlesFilter{StarredOnly: true},
+			countFilter: store.ArticlesFilter{StarredOnly: true},
+		},
+		{
+			id:          "view/today",
+			title:       "Today",
+			filter:      store.ArticlesFilter{PublishedAfter: today},
+			countFilter: store.ArticlesFilter{PublishedAfter: today, UnreadOnly: true},
+		},
+		{
+			id:          "view/all",
+			title:       "All articles",
+			countFilter: store.ArticlesFilter{UnreadOnly: true},
+			stream:      freshrss.StateReadingList,
+		},
+	}
+}
+
+func loadSmartViews(ctx context.Context, db *store.Sqlite) ([]smartView, error) {
+	views := smartViews(time.Now())
+	for i := range views {
+		n, err := db.CountArticles(ctx, views[i].countFilter)
+		if err != nil {
+			return nil, err
+		}
+		views[i].count = n
+	}
+	return views, nil
+}
+
+// matches reports whether the article is counted by the filter,
+// only status and publish time filters are checked.
+func matches(f store.ArticlesFilter, a store.Article) bool {
+	return (!f.UnreadOnly || !a.IsRead) &&
+		(!f.StarredOnly || a.IsStarred) &&
+		a.PublishedAt >= f.PublishedAfter
+}
+
+func countDelta(f store.ArticlesFilter, from, to store.Article) int {
+	switch {
+	case !matches(f, from) && matches(f, to):
+		return 1
+	case matches(f, from) && !matches(f, to):
+		return -1
+	default:
+		return 0
+	}
+}
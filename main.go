@@ -29,6 +29,7 @@ func main() {
 		Commands: []*cli.Command{
 			initConfigCmd,
 			syncFeedsCmd,
+			folderCmd,
 		},
 	}
 	if err := cmd.Run(context.Background(), os.Args); err != nil {
@@ -44,9 +45,19 @@ func runTui(ctx context.Context, c *cli.Command) error {
 	}
 	go func() { app.freshrssWorker.Run(ctx) }()
 
-	model := tui.NewModel(ctx, app.freshrssSyncer, app.store)
-	_, err = tea.NewProgram(model).Run()
-	return err
+	model, err := tui.NewModel(ctx, app.cfg, app.freshrssSyncer, app.freshrssWorker, app.freshrssEditor, app.store)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tea.NewProgram(model).Run(); err != nil {
+		return err
+	}
+
+	if w := model.Warning(); w != "" {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	return nil
 }
 
 // sync
@@ -66,6 +77,51 @@ func syncFeeds(ctx context.Context, c *cli.Command) error {
 	return app.freshrssSyncer.Sync(ctx)
 }
 
+// folder
+
+var folderCmd = &cli.Command{
+	Name:  "folder",
+	Usage: "Manage folders.",
+	Commands: []*cli.Command{
+		{
+			Name:      "rename",
+			Usage:     "Rename a folder, its feeds are kept.",
+			ArgsUsage: "<name> <new name>",
+			Action:    renameFolder,
+		},
+		{
+			Name:      "delete",
+			Usage:     "Delete a folder, its feeds are left without a folder.",
+			ArgsUsage: "<name>",
+			Action:    deleteFolder,
+		},
+	},
+}
+
+func renameFolder(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 2 {
+		return fmt.Errorf("usage: %s %s", c.FullName(), c.ArgsUsage)
+	}
+
+	app, err := bootstrap(ctx, false)
+	if err != nil {
+		return err
+	}
+	return app.freshrssEditor.RenameFolder(ctx, c.Args().Get(0), c.Args().Get(1))
+}
+
+func deleteFolder(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("usage: %s %s", c.FullName(), c.ArgsUsage)
+	}
+
+	app, err := bootstrap(ctx, false)
+	if err != nil {
+		return err
+	}
+	return app.freshrssEditor.DeleteFolder(ctx, c.Args().First())
+}
+
 // init
 
 var initConfigCmd = &cli.Command{